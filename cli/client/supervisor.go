@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Service is a unit of work a Supervisor restarts on failure, the same
+// shape as suture v4's Service interface: Serve runs until ctx is
+// cancelled (clean shutdown, return nil) or it hits a transient failure
+// (return a non-nil error to request a restart).
+type Service interface {
+	Serve(ctx context.Context) error
+}
+
+// ServiceFunc adapts a plain function to a Service.
+type ServiceFunc func(ctx context.Context) error
+
+// Serve implements Service.
+func (f ServiceFunc) Serve(ctx context.Context) error { return f(ctx) }
+
+// Supervisor restarts a Service with exponential backoff and jitter
+// whenever it returns a non-nil error, the way Syncthing supervises its
+// connection goroutines under suture v4, and stops cleanly once ctx is
+// cancelled instead of retrying.
+type Supervisor struct {
+	// MaxRetries caps how many times Serve restarts svc before giving up
+	// and calling OnGiveUp. Zero means retry forever.
+	MaxRetries int
+	// MaxBackoff caps the exponential backoff between restarts. Zero
+	// means a default of 30s.
+	MaxBackoff time.Duration
+	// OnRetry, if set, is called before each restart with the 1-based
+	// attempt number and the error that triggered it.
+	OnRetry func(attempt int, err error)
+	// OnGiveUp, if set, is called once MaxRetries is exceeded.
+	OnGiveUp func(err error)
+}
+
+// baseBackoff is the delay before the first restart attempt.
+const baseBackoff = 250 * time.Millisecond
+
+// Serve runs svc under ctx, restarting it with exponential backoff and
+// jitter each time it returns a non-nil error. It returns once ctx is
+// cancelled, svc shuts down cleanly (nil error), or MaxRetries is
+// exceeded.
+func (s *Supervisor) Serve(ctx context.Context, svc Service) {
+	attempt := 0
+	for {
+		err := svc.Serve(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		attempt++
+		if s.MaxRetries > 0 && attempt > s.MaxRetries {
+			if s.OnGiveUp != nil {
+				s.OnGiveUp(err)
+			}
+			return
+		}
+
+		if s.OnRetry != nil {
+			s.OnRetry(attempt, err)
+		}
+
+		select {
+		case <-time.After(s.backoffFor(attempt)):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// backoffFor returns 2^(attempt-1) * baseBackoff, capped at MaxBackoff
+// (default 30s), then jittered to a random duration between half and all
+// of that value so reconnecting clients don't all retry in lockstep.
+func (s *Supervisor) backoffFor(attempt int) time.Duration {
+	maxBackoff := s.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	backoff := baseBackoff * time.Duration(1<<uint(attempt-1))
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+	return backoff/2 + jitter
+}
@@ -1,11 +1,15 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -20,14 +24,44 @@ const (
 	TypeCommandOutput   MessageType = "command_output"
 	TypeRetryRequest    MessageType = "retry_request"
 	TypeError           MessageType = "error"
+	TypeToolCall        MessageType = "tool_call"
+	TypeToolResult      MessageType = "tool_result"
+	TypeCommandEdit     MessageType = "command_edit"
+	TypeMessageChunk    MessageType = "message_chunk"
+	TypeMessageEnd      MessageType = "message_end"
+	TypeCancel          MessageType = "cancel"
 )
 
+// ToolCall is a structured tool invocation requested by the agent, the
+// way LLM agents expose typed tools (read_file, write_file, http_get,
+// kubectl_get, ...) instead of a raw shell command line. Scope is a
+// short agent-defined label (e.g. "read", "write", "network") that lets
+// the TUI and per-tool approval policies key off the kind of access
+// being requested rather than the tool name alone.
+type ToolCall struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Args  json.RawMessage `json:"args,omitempty"`
+	Scope string          `json:"scope,omitempty"`
+}
+
+// ConversationContext tags a TypeMessage with the persisted conversation
+// (and, when forking, the tree node) it belongs to, so the server can
+// resolve that node's ancestor chain from its own mirror of the tree
+// instead of the client replaying every prior turn on every message.
+type ConversationContext struct {
+	ConversationID string `json:"conversation_id"`
+	NodeID         string `json:"node_id,omitempty"`
+}
+
 // ClientMessage represents messages sent from the client to the server
 type ClientMessage struct {
-	Type     MessageType `json:"type"`
-	Content  string      `json:"content,omitempty"`
-	Approved bool        `json:"approved,omitempty"`
-	Retry    bool        `json:"retry,omitempty"`
+	Type       MessageType          `json:"type"`
+	Content    string               `json:"content,omitempty"`
+	Approved   bool                 `json:"approved,omitempty"`
+	Retry      bool                 `json:"retry,omitempty"`
+	ToolCallID string               `json:"tool_call_id,omitempty"`
+	Context    *ConversationContext `json:"context,omitempty"`
 }
 
 // ServerMessage represents messages received from the server
@@ -40,6 +74,24 @@ type ServerMessage struct {
 	RetryCount  int          `json:"retry_count,omitempty"`
 	Output      string       `json:"output,omitempty"`
 	Success     bool         `json:"success,omitempty"`
+	Tool        *ToolCall    `json:"tool,omitempty"`
+	ToolCallID  string       `json:"tool_call_id,omitempty"`
+	// ID identifies a TypeCommandApproval message's suggestion so a later
+	// TypeCommandOutput carrying the same ID can be linked back to it,
+	// the plain-command counterpart to ToolCallID for structured tools.
+	ID string `json:"id,omitempty"`
+	// Usage carries the token accounting for a TypeMessage or
+	// TypeMessageEnd reply, when the server reports it.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Usage reports the token accounting for one agent reply. It's only
+// populated when the server includes it, since only the server (which
+// calls the LLM) knows the actual counts - the client never estimates
+// or fabricates a figure of its own.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 // CommandField handles both string and []string formats from the server
@@ -65,94 +117,308 @@ func (cf *CommandField) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("command field must be string or []string")
 }
 
-// AgentClient handles communication with the agent server
+// defaultSendBuffer sizes the outbound queue writePump drains; generous
+// enough that a user approving a command or sending a message never
+// blocks on a slow or momentarily reconnecting link.
+const defaultSendBuffer = 32
+
+// defaultPingInterval is how often pingPump sends an application-level
+// WebSocket ping when PingInterval is left at its zero value.
+const defaultPingInterval = 20 * time.Second
+
+// defaultReadTimeout bounds how long readPump will wait for a message (or
+// a pong extending the deadline) before the connection is considered dead.
+// It must be comfortably longer than the ping interval so a pong delayed
+// by ordinary jitter doesn't trip it.
+const defaultReadTimeout = 60 * time.Second
+
+// AgentClient handles communication with the agent server. Its readPump
+// and writePump run as Supervisor-managed Services: a dropped connection
+// is redialed with exponential backoff and jitter instead of leaving the
+// client silently dead after the first ReadMessage error, the way
+// Syncthing supervises its connection goroutines under suture v4.
 type AgentClient struct {
-	conn              *websocket.Conn
-	serverURL         string
-	connected         bool
-	connectionMutex   sync.Mutex
-	sendMutex         sync.Mutex
-	done              chan struct{}
-	onMessage         func(string)
-	onCommandApproval func([]string, string)
-	onCommandOutput   func(string)
+	conn            *websocket.Conn
+	serverURL       string
+	connected       bool
+	connectionMutex sync.Mutex
+	sendChan        chan ClientMessage
+	ctx             context.Context
+	cancel          context.CancelFunc
+
+	// writeMutex serializes every write to conn (writePump's WriteJSON,
+	// pingPump's WriteMessage, and Close's close frame) - gorilla/websocket
+	// permits only one concurrent writer, and these three run from
+	// independent goroutines.
+	writeMutex sync.Mutex
+
+	// MaxRetries caps how many times a dropped connection is redialed
+	// before giving up. Zero (the default) retries forever.
+	MaxRetries int
+	// MaxBackoff caps the exponential backoff between redial attempts.
+	// Zero means the Supervisor default of 30s.
+	MaxBackoff time.Duration
+	// PingInterval is how often pingPump sends an application-level
+	// WebSocket ping to detect a half-open connection. Zero uses
+	// defaultPingInterval.
+	PingInterval time.Duration
+	// ReadTimeout bounds how long readPump waits for a message or pong
+	// before the connection is considered dead. Zero uses
+	// defaultReadTimeout.
+	ReadTimeout time.Duration
+
+	proxyHeaders http.Header
+	tlsConfig    *tls.Config
+	bearerToken  string
+
+	onMessage         func(content string, usage *Usage)
+	onCommandApproval func(command []string, explanation string, id string)
+	onCommandOutput   func(output string, id string)
 	onRetryRequest    func(string, int)
 	onError           func(string)
 	onConnectionLost  func()
+	onReconnecting    func(attempt int)
+	onToolCall        func(ToolCall)
+	onToolResult      func(toolCallID string, output string, success bool)
+	onMessageChunk    func(string)
+	onMessageEnd      func(usage *Usage)
+}
+
+// Option configures optional AgentClient dial behavior beyond the
+// required serverURL, e.g. for a reverse-proxied or mTLS-secured
+// deployment.
+type Option func(*AgentClient)
+
+// WithProxyHeaders sends extra headers (X-Real-IP, X-Forwarded-For, a
+// trusted-proxy shared secret, ...) on the WebSocket upgrade request, the
+// way nextcloud-spreed-signaling's reverse-proxy handling expects the
+// client side of the handshake to identify itself.
+func WithProxyHeaders(headers http.Header) Option {
+	return func(c *AgentClient) {
+		c.proxyHeaders = headers
+	}
+}
+
+// WithTLSConfig installs a custom tls.Config (custom CA, mTLS client
+// cert) for wss:// connections instead of websocket.DefaultDialer's bare
+// TLS defaults.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *AgentClient) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithBearerToken sends token as an "Authorization: Bearer" header on the
+// dial handshake, e.g. one retrieved via auth.GetAPIKey, instead of
+// embedding it in the server URL's query string where an intermediary
+// proxy's access log could capture it.
+func WithBearerToken(token string) Option {
+	return func(c *AgentClient) {
+		c.bearerToken = token
+	}
 }
 
 // NewAgentClient creates a new agent client
-func NewAgentClient(serverURL string) *AgentClient {
-	return &AgentClient{
+func NewAgentClient(serverURL string, opts ...Option) *AgentClient {
+	c := &AgentClient{
 		serverURL: serverURL,
-		done:      make(chan struct{}),
+		sendChan:  make(chan ClientMessage, defaultSendBuffer),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// Connect establishes a connection to the WebSocket server
+// Connect dials the WebSocket server and starts the supervised readPump
+// and writePump. Call Disconnect (not a second Connect) to tear them
+// down; Connect returns nil without redialing if already connected.
 func (c *AgentClient) Connect() error {
 	c.connectionMutex.Lock()
-	defer c.connectionMutex.Unlock()
-
 	if c.connected {
+		c.connectionMutex.Unlock()
 		return nil
 	}
 
-	var err error
-	c.conn, _, err = websocket.DefaultDialer.Dial(c.serverURL, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ctx = ctx
+	c.cancel = cancel
+
+	if err := c.dialLocked(); err != nil {
+		cancel()
+		c.connectionMutex.Unlock()
+		return err
+	}
+	c.connectionMutex.Unlock()
+
+	supervisor := &Supervisor{
+		MaxRetries: c.MaxRetries,
+		MaxBackoff: c.MaxBackoff,
+		OnRetry: func(attempt int, err error) {
+			log.Printf("agent connection lost, reconnecting (attempt %d): %v", attempt, err)
+			if c.onReconnecting != nil {
+				c.onReconnecting(attempt)
+			}
+		},
+		OnGiveUp: func(err error) {
+			log.Printf("agent connection gave up after %d attempts: %v", c.MaxRetries, err)
+		},
+	}
+	go supervisor.Serve(ctx, ServiceFunc(c.serveConnection))
+
+	return nil
+}
+
+// dialLocked dials c.serverURL and marks the client connected. Callers
+// must hold connectionMutex.
+func (c *AgentClient) dialLocked() error {
+	dialer := websocket.DefaultDialer
+	if c.tlsConfig != nil {
+		d := *websocket.DefaultDialer
+		d.TLSClientConfig = c.tlsConfig
+		dialer = &d
+	}
+
+	header := c.proxyHeaders.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if c.bearerToken != "" {
+		header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	conn, _, err := dialer.Dial(c.serverURL, header)
 	if err != nil {
 		return fmt.Errorf("websocket connection failed: %w", err)
 	}
 
-	c.connected = true
+	conn.SetReadDeadline(time.Now().Add(c.readTimeout()))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(c.readTimeout()))
+		return nil
+	})
 
-	// Start listening for messages
-	go c.readPump()
+	c.conn = conn
+	c.connected = true
 	return nil
 }
 
-// SendMessage sends a message to the agent
-func (c *AgentClient) SendMessage(content string) error {
+// pingInterval returns PingInterval, or defaultPingInterval if unset.
+func (c *AgentClient) pingInterval() time.Duration {
+	if c.PingInterval > 0 {
+		return c.PingInterval
+	}
+	return defaultPingInterval
+}
+
+// readTimeout returns ReadTimeout, or defaultReadTimeout if unset.
+func (c *AgentClient) readTimeout() time.Duration {
+	if c.ReadTimeout > 0 {
+		return c.ReadTimeout
+	}
+	return defaultReadTimeout
+}
+
+// serveConnection is the Service the Supervisor restarts: it (re)dials
+// if necessary, runs readPump, writePump and pingPump against that
+// connection, and returns the first error any of them produces so the
+// Supervisor can redial with backoff. It returns nil only when ctx is
+// cancelled, signalling a clean shutdown rather than a transient failure.
+//
+// The three pumps run under connCtx, a child of ctx scoped to this one
+// connection, and are handed conn as a local value rather than reading
+// c.conn themselves: on a transient failure (one pump errors, the select
+// below returns, conn is closed) connCancel stops the other two
+// immediately instead of leaving them running against a connection the
+// next serveConnection call is about to replace. Without that, a stale
+// writePump could steal a message off the shared sendChan and write it to
+// whatever connection c.conn happens to hold by the time it wakes up.
+func (c *AgentClient) serveConnection(ctx context.Context) error {
 	c.connectionMutex.Lock()
 	if !c.connected {
-		c.connectionMutex.Unlock()
-		return fmt.Errorf("client not connected")
+		if err := c.dialLocked(); err != nil {
+			c.connectionMutex.Unlock()
+			return err
+		}
 	}
+	conn := c.conn
 	c.connectionMutex.Unlock()
 
-	c.sendMutex.Lock()
-	defer c.sendMutex.Unlock()
+	connCtx, connCancel := context.WithCancel(ctx)
+	defer connCancel()
 
-	message := ClientMessage{
-		Type:    TypeMessage,
-		Content: content,
+	errCh := make(chan error, 3)
+	go func() { errCh <- c.readPump(connCtx, conn) }()
+	go func() { errCh <- c.writePump(connCtx, conn) }()
+	go func() { errCh <- c.pingPump(connCtx, conn) }()
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
 	}
+	connCancel()
 
-	err := c.conn.WriteJSON(message)
-	if err != nil {
-		log.Printf("Error sending message: %v", err)
+	c.connectionMutex.Lock()
+	if c.conn == conn {
+		conn.Close()
+	}
+	wasConnected := c.connected
+	c.connected = false
+	c.connectionMutex.Unlock()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	if wasConnected && c.onConnectionLost != nil {
+		c.onConnectionLost()
 	}
 	return err
 }
 
-// SendApproval sends an approval response for a command
-func (c *AgentClient) SendApproval(approved bool) error {
+// enqueue hands message to writePump, returning an error instead of
+// blocking forever if the client isn't connected or is shutting down.
+func (c *AgentClient) enqueue(message ClientMessage) error {
 	c.connectionMutex.Lock()
-	if !c.connected {
-		c.connectionMutex.Unlock()
-		return fmt.Errorf("client not connected")
-	}
+	connected := c.connected
+	ctx := c.ctx
 	c.connectionMutex.Unlock()
 
-	c.sendMutex.Lock()
-	defer c.sendMutex.Unlock()
+	if !connected {
+		return fmt.Errorf("client not connected")
+	}
 
-	message := ClientMessage{
-		Type:     TypeCommandApproval,
-		Approved: approved,
+	select {
+	case c.sendChan <- message:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("client shutting down")
 	}
+}
 
-	return c.conn.WriteJSON(message)
+// SendMessage sends a message to the agent
+func (c *AgentClient) SendMessage(content string) error {
+	return c.enqueue(ClientMessage{Type: TypeMessage, Content: content})
+}
+
+// ResumeConversation tells the server which persisted conversation the
+// next SendMessage belongs to, so its reply appends to that
+// conversation's existing node tree instead of starting a new thread.
+func (c *AgentClient) ResumeConversation(id string) error {
+	return c.enqueue(ClientMessage{Type: TypeMessage, Context: &ConversationContext{ConversationID: id}})
+}
+
+// ForkFrom sends newContent as a reply to nodeID, forking a new branch
+// from that point in the tree rather than continuing its existing head -
+// the server resolves nodeID's ancestor chain from its own mirror of the
+// conversation instead of the client replaying every prior turn.
+func (c *AgentClient) ForkFrom(nodeID string, newContent string) error {
+	return c.enqueue(ClientMessage{Type: TypeMessage, Content: newContent, Context: &ConversationContext{NodeID: nodeID}})
+}
+
+// SendApproval sends an approval response for a command
+func (c *AgentClient) SendApproval(approved bool) error {
+	return c.enqueue(ClientMessage{Type: TypeCommandApproval, Approved: approved})
 }
 
 // SendCommandApproval sends an approval response for a command (alias for SendApproval)
@@ -160,34 +426,47 @@ func (c *AgentClient) SendCommandApproval(approved bool) error {
 	return c.SendApproval(approved)
 }
 
+// SendCommandEdit sends a user-edited version of a suggested command back
+// to the server, distinct from SendCommandApproval's yes/no so the server
+// can tell "run this instead" from a plain approval or rejection.
+func (c *AgentClient) SendCommandEdit(command []string) error {
+	return c.enqueue(ClientMessage{Type: TypeCommandEdit, Content: strings.Join(command, " ")})
+}
+
 // SendRetryResponse sends a retry response for a failed command
 func (c *AgentClient) SendRetryResponse(retry bool) error {
-	c.connectionMutex.Lock()
-	if !c.connected {
-		c.connectionMutex.Unlock()
-		return fmt.Errorf("client not connected")
-	}
-	c.connectionMutex.Unlock()
-
-	c.sendMutex.Lock()
-	defer c.sendMutex.Unlock()
+	return c.enqueue(ClientMessage{Type: TypeRetryResponse, Retry: retry})
+}
 
-	message := ClientMessage{
-		Type:  TypeRetryResponse,
-		Retry: retry,
-	}
+// SendCancel asks the server to stop an in-progress streaming reply, the
+// websocket-path counterpart to closing the direct llmProvider path's
+// stopSignal channel.
+func (c *AgentClient) SendCancel() error {
+	return c.enqueue(ClientMessage{Type: TypeCancel})
+}
 
-	return c.conn.WriteJSON(message)
+// SendToolApproval sends an approval response for a pending tool call,
+// identified by the ToolCall.ID it answers.
+func (c *AgentClient) SendToolApproval(toolCallID string, approved bool) error {
+	return c.enqueue(ClientMessage{Type: TypeCommandApproval, ToolCallID: toolCallID, Approved: approved})
 }
 
-// Disconnect closes the WebSocket connection gracefully
+// Disconnect cancels the supervisor's context, stopping readPump and
+// writePump cleanly instead of closing a done channel that Close could
+// otherwise double-close.
 func (c *AgentClient) Disconnect() {
 	c.Close()
 }
 
-// Close closes the WebSocket connection
+// Close cancels the connection context and closes the underlying
+// WebSocket connection. It's safe to call more than once.
 func (c *AgentClient) Close() {
-	close(c.done)
+	c.connectionMutex.Lock()
+	cancel := c.cancel
+	c.connectionMutex.Unlock()
+	if cancel != nil {
+		cancel()
+	}
 
 	c.connectionMutex.Lock()
 	defer c.connectionMutex.Unlock()
@@ -196,11 +475,18 @@ func (c *AgentClient) Close() {
 		return
 	}
 
+	// Force any in-flight ReadMessage to return immediately instead of
+	// blocking until readTimeout, the way cancelling ctx alone can't: ctx
+	// is only checked between reads, not while one is in progress.
+	c.conn.SetReadDeadline(time.Now())
+
 	// Send close message
+	c.writeMutex.Lock()
 	err := c.conn.WriteMessage(
 		websocket.CloseMessage,
 		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
 	)
+	c.writeMutex.Unlock()
 	if err != nil {
 		log.Println("Error during connection close:", err)
 	}
@@ -217,18 +503,23 @@ func (c *AgentClient) IsConnected() bool {
 	return c.connected
 }
 
-// SetMessageHandler sets the callback for text messages
-func (c *AgentClient) SetMessageHandler(handler func(string)) {
+// SetMessageHandler sets the callback for text messages. usage is
+// non-nil when the server reported token accounting for this reply.
+func (c *AgentClient) SetMessageHandler(handler func(content string, usage *Usage)) {
 	c.onMessage = handler
 }
 
-// SetCommandApprovalHandler sets the callback for command approval requests
-func (c *AgentClient) SetCommandApprovalHandler(handler func([]string, string)) {
+// SetCommandApprovalHandler sets the callback for command approval
+// requests. id identifies this suggestion so the matching
+// SetCommandOutputHandler call can be linked back to it.
+func (c *AgentClient) SetCommandApprovalHandler(handler func(command []string, explanation string, id string)) {
 	c.onCommandApproval = handler
 }
 
-// SetCommandOutputHandler sets the callback for command output
-func (c *AgentClient) SetCommandOutputHandler(handler func(string)) {
+// SetCommandOutputHandler sets the callback for command output. id, when
+// non-empty, names the TypeCommandApproval suggestion this output
+// resolves.
+func (c *AgentClient) SetCommandOutputHandler(handler func(output string, id string)) {
 	c.onCommandOutput = handler
 }
 
@@ -247,75 +538,183 @@ func (c *AgentClient) SetConnectionLostHandler(handler func()) {
 	c.onConnectionLost = handler
 }
 
-// readPump handles incoming messages
-func (c *AgentClient) readPump() {
-	defer func() {
-		c.connectionMutex.Lock()
-		if c.conn != nil {
-			c.conn.Close()
+// SetReconnectHandler sets the callback fired before each redial attempt
+// after a dropped connection, with the 1-based attempt number, so the
+// TUI can show "reconnecting (attempt N)..." instead of going silent.
+func (c *AgentClient) SetReconnectHandler(handler func(attempt int)) {
+	c.onReconnecting = handler
+}
+
+// SetToolCallHandler sets the callback for structured tool-call requests
+func (c *AgentClient) SetToolCallHandler(handler func(ToolCall)) {
+	c.onToolCall = handler
+}
+
+// SetToolResultHandler sets the callback for a tool call's execution
+// result, identified by the ToolCall.ID it answers.
+func (c *AgentClient) SetToolResultHandler(handler func(toolCallID string, output string, success bool)) {
+	c.onToolResult = handler
+}
+
+// SetMessageChunkHandler sets the callback for one chunk of a streaming
+// agent reply, the websocket-path counterpart to the direct llmProvider
+// path's per-chunk channel.
+func (c *AgentClient) SetMessageChunkHandler(handler func(string)) {
+	c.onMessageChunk = handler
+}
+
+// SetMessageEndHandler sets the callback fired once a streaming agent
+// reply has finished, so the accumulated chunks can be committed as a
+// single message. usage is non-nil when the server reported token
+// accounting for the reply that just finished.
+func (c *AgentClient) SetMessageEndHandler(handler func(usage *Usage)) {
+	c.onMessageEnd = handler
+}
+
+// SetPingInterval overrides how often pingPump sends an application-level
+// WebSocket ping to detect a half-open connection.
+func (c *AgentClient) SetPingInterval(d time.Duration) {
+	c.PingInterval = d
+}
+
+// SetReadTimeout overrides how long readPump waits for a message or pong
+// before the connection is considered dead.
+func (c *AgentClient) SetReadTimeout(d time.Duration) {
+	c.ReadTimeout = d
+}
+
+// readPump handles incoming messages on conn, the connection serveConnection
+// dialed for this run; it never reads c.conn directly so a stale readPump
+// from a prior, already-closed connection can't be confused with the
+// current one. It returns nil when ctx is cancelled and a non-nil error on
+// any other read failure, which serveConnection treats as a signal to
+// redial.
+func (c *AgentClient) readPump(ctx context.Context, conn *websocket.Conn) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
 		}
-		wasConnected := c.connected
-		c.connected = false
-		c.connectionMutex.Unlock()
 
-		// Notify about connection loss
-		if wasConnected && c.onConnectionLost != nil {
-			c.onConnectionLost()
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if websocket.IsUnexpectedCloseError(
+				err,
+				websocket.CloseGoingAway,
+				websocket.CloseAbnormalClosure,
+			) {
+				log.Printf("websocket error: %v", err)
+			}
+			return err
+		}
+
+		// Parse message
+		var serverMsg ServerMessage
+		if err := json.Unmarshal(message, &serverMsg); err != nil {
+			log.Printf("Error parsing message: %v", err)
+			continue
 		}
-	}()
 
+		// Handle message based on type
+		switch serverMsg.Type {
+		case TypeMessage:
+			if c.onMessage != nil {
+				c.onMessage(serverMsg.Content, serverMsg.Usage)
+			}
+		case TypeCommandApproval:
+			if c.onCommandApproval != nil {
+				c.onCommandApproval(serverMsg.Command, serverMsg.Explanation, serverMsg.ID)
+			}
+		case TypeCommandOutput:
+			if c.onCommandOutput != nil {
+				// Use Output field for command output, fallback to Content if Output is empty
+				output := serverMsg.Output
+				if output == "" {
+					output = serverMsg.Content
+				}
+				c.onCommandOutput(output, serverMsg.ID)
+			}
+		case TypeRetryRequest:
+			if c.onRetryRequest != nil {
+				c.onRetryRequest(serverMsg.Content, serverMsg.RetryCount)
+			}
+		case TypeError:
+			if c.onError != nil {
+				c.onError(serverMsg.Error)
+			}
+		case TypeToolCall:
+			if c.onToolCall != nil && serverMsg.Tool != nil {
+				c.onToolCall(*serverMsg.Tool)
+			}
+		case TypeToolResult:
+			if c.onToolResult != nil {
+				c.onToolResult(serverMsg.ToolCallID, serverMsg.Output, serverMsg.Success)
+			}
+		case TypeMessageChunk:
+			if c.onMessageChunk != nil {
+				c.onMessageChunk(serverMsg.Content)
+			}
+		case TypeMessageEnd:
+			if c.onMessageEnd != nil {
+				c.onMessageEnd(serverMsg.Usage)
+			}
+		}
+	}
+}
+
+// writePump drains sendChan onto conn, the connection serveConnection
+// dialed for this run. It implements Service alongside readPump: both run
+// under serveConnection's per-connection context, and a write failure
+// returns here the same way a read failure does in readPump, triggering a
+// Supervisor-driven redial. Writing to the conn parameter rather than
+// c.conn means a writePump outliving its connection's ctx can never steal
+// a message off the shared sendChan and write it to a connection a later
+// serveConnection call redialed.
+func (c *AgentClient) writePump(ctx context.Context, conn *websocket.Conn) error {
 	for {
 		select {
-		case <-c.done:
-			return
-		default:
-			// Read message
-			_, message, err := c.conn.ReadMessage()
+		case <-ctx.Done():
+			return nil
+		case message := <-c.sendChan:
+			c.writeMutex.Lock()
+			conn.SetWriteDeadline(time.Now().Add(c.pingInterval()))
+			err := conn.WriteJSON(message)
+			c.writeMutex.Unlock()
 			if err != nil {
-				if websocket.IsUnexpectedCloseError(
-					err,
-					websocket.CloseGoingAway,
-					websocket.CloseAbnormalClosure,
-				) {
-					log.Printf("websocket error: %v", err)
-				}
-				return
+				log.Printf("Error sending message: %v", err)
+				return err
 			}
+		}
+	}
+}
 
-			// Parse message
-			var serverMsg ServerMessage
-			if err := json.Unmarshal(message, &serverMsg); err != nil {
-				log.Printf("Error parsing message: %v", err)
-				continue
-			}
+// pingPump sends an application-level ping on conn every pingInterval so a
+// half-open connection (the peer vanished without a TCP FIN/RST) is
+// caught by readTimeout instead of readPump blocking on ReadMessage
+// forever. The pong handler installed in dialLocked extends the read
+// deadline on each reply, the same cadence netstack's gonet uses a
+// deadlineTimer for - here the deadline lives directly on the
+// gorilla/websocket connection instead of a separate timer type. Writing
+// to the conn parameter rather than the mutable c.conn field means a
+// pingPump left over from a previous connection can never ping (or, worse,
+// race a write against) the connection a later redial installed.
+func (c *AgentClient) pingPump(ctx context.Context, conn *websocket.Conn) error {
+	ticker := time.NewTicker(c.pingInterval())
+	defer ticker.Stop()
 
-			// Handle message based on type
-			switch serverMsg.Type {
-			case TypeMessage:
-				if c.onMessage != nil {
-					c.onMessage(serverMsg.Content)
-				}
-			case TypeCommandApproval:
-				if c.onCommandApproval != nil {
-					c.onCommandApproval(serverMsg.Command, serverMsg.Explanation)
-				}
-			case TypeCommandOutput:
-				if c.onCommandOutput != nil {
-					// Use Output field for command output, fallback to Content if Output is empty
-					output := serverMsg.Output
-					if output == "" {
-						output = serverMsg.Content
-					}
-					c.onCommandOutput(output)
-				}
-			case TypeRetryRequest:
-				if c.onRetryRequest != nil {
-					c.onRetryRequest(serverMsg.Content, serverMsg.RetryCount)
-				}
-			case TypeError:
-				if c.onError != nil {
-					c.onError(serverMsg.Error)
-				}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.writeMutex.Lock()
+			conn.SetWriteDeadline(time.Now().Add(c.pingInterval()))
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			c.writeMutex.Unlock()
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"cloud-assist/internal/docker"
+)
+
+// columnSplit matches a column separator in table output: a single tab
+// (the Engine-backed Service's tables) or a run of two-or-more spaces
+// (docker CLI-style tables, as in mock.DockerCommandService's fixtures).
+var columnSplit = regexp.MustCompile(`\t|\s{2,}`)
+
+// dockerCompletionProvider implements ui.CompletionProvider against a
+// live CommandService, parsing the table output "docker ps -a",
+// "docker images", and "docker network ls" already return for the chat
+// UI, rather than adding a second code path that talks to the daemon
+// directly.
+type dockerCompletionProvider struct {
+	service docker.CommandService
+}
+
+// newDockerCompletionProvider wraps service as a ui.CompletionProvider.
+func newDockerCompletionProvider(service docker.CommandService) *dockerCompletionProvider {
+	return &dockerCompletionProvider{service: service}
+}
+
+// Containers returns the NAMES column of "docker ps -a".
+func (p *dockerCompletionProvider) Containers() []string {
+	return p.column("docker ps -a", "NAMES")
+}
+
+// Images returns "REPOSITORY:TAG" for each row of "docker images".
+func (p *dockerCompletionProvider) Images() []string {
+	output, err := p.service.ExecuteCommand("docker images")
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+	repoIdx, tagIdx := -1, -1
+	for i, header := range columnSplit.Split(strings.TrimSpace(lines[0]), -1) {
+		switch header {
+		case "REPOSITORY":
+			repoIdx = i
+		case "TAG":
+			tagIdx = i
+		}
+	}
+	if repoIdx == -1 || tagIdx == -1 {
+		return nil
+	}
+
+	var images []string
+	for _, line := range lines[1:] {
+		fields := columnSplit.Split(strings.TrimSpace(line), -1)
+		if len(fields) <= repoIdx || len(fields) <= tagIdx {
+			continue
+		}
+		images = append(images, fields[repoIdx]+":"+fields[tagIdx])
+	}
+	return images
+}
+
+// Networks returns the NAME column of "docker network ls".
+func (p *dockerCompletionProvider) Networks() []string {
+	return p.column("docker network ls", "NAME")
+}
+
+// column runs command and returns the values of its header column,
+// splitting each line with columnSplit.
+func (p *dockerCompletionProvider) column(command, header string) []string {
+	output, err := p.service.ExecuteCommand(command)
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+	index := -1
+	for i, col := range columnSplit.Split(strings.TrimSpace(lines[0]), -1) {
+		if col == header {
+			index = i
+		}
+	}
+	if index == -1 {
+		return nil
+	}
+
+	var values []string
+	for _, line := range lines[1:] {
+		fields := columnSplit.Split(strings.TrimSpace(line), -1)
+		if len(fields) <= index {
+			continue
+		}
+		values = append(values, fields[index])
+	}
+	return values
+}
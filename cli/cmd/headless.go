@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+
+	"cloud-assist/internal/mock"
+)
+
+// headlessFlags holds the command-line flags that let cloud-assist run
+// non-interactively in CI: execute the recommended remediation, wait for
+// health, and post the outcome to a notifier instead of rendering the
+// TUI.
+type headlessFlags struct {
+	headless       bool
+	reportTemplate string
+	notify         string
+}
+
+// parseHeadlessFlags registers and parses the headless-mode flags. It's
+// called once at the top of main().
+func parseHeadlessFlags() headlessFlags {
+	var f headlessFlags
+	flag.BoolVar(&f.headless, "headless", false, "run non-interactively against the local Docker daemon, then exit")
+	flag.StringVar(&f.reportTemplate, "report-template", "", "path to a text/template file customizing the session report notification")
+	flag.StringVar(&f.notify, "notify", "stdout", "where to send the session report: stdout, json:<path>, slack:<webhook-url>, webhook:<url>")
+	flag.Parse()
+	return f
+}
+
+// resolveNotifier builds the Notifier named by spec, loading
+// reportTemplatePath (if set) to customize its rendering.
+func resolveNotifier(spec, reportTemplatePath string) (mock.Notifier, error) {
+	var tmpl *template.Template
+	if reportTemplatePath != "" {
+		loaded, err := mock.LoadReportTemplate(reportTemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		tmpl = loaded
+	}
+
+	switch {
+	case spec == "" || spec == "stdout":
+		return mock.StdoutNotifier{Template: tmpl}, nil
+	case strings.HasPrefix(spec, "json:"):
+		return mock.JSONFileNotifier{Path: strings.TrimPrefix(spec, "json:")}, nil
+	case strings.HasPrefix(spec, "slack:"):
+		return mock.SlackWebhookNotifier{URL: strings.TrimPrefix(spec, "slack:"), Template: tmpl}, nil
+	case strings.HasPrefix(spec, "webhook:"):
+		return mock.WebhookNotifier{URL: strings.TrimPrefix(spec, "webhook:"), Template: tmpl}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized --notify value: %s", spec)
+	}
+}
+
+// runHeadless drives one remediation cycle without the TUI: it asks
+// AgentService for its first command suggestion, approves it
+// automatically, lets any post-execution WaitStrategy verify the
+// result, then flushes the accumulated SessionReport through notifier.
+// A SIGINT (Ctrl+C, or the CI runner canceling the job) flushes the
+// report early instead of losing it.
+func runHeadless(flags headlessFlags) error {
+	notifier, err := resolveNotifier(flags.notify, flags.reportTemplate)
+	if err != nil {
+		return err
+	}
+
+	backend, err := mock.NewDockerEngineBackend()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker daemon: %w", err)
+	}
+	agent := mock.NewAgentService(backend)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigCh:
+			fmt.Fprintln(os.Stderr, "interrupted, flushing session report...")
+			_ = notifier.Notify(agent.Report())
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	defer close(done)
+
+	for _, msg := range agent.ProcessUserMessage("") {
+		fmt.Println(msg.Content)
+	}
+	for _, msg := range agent.ProcessUserMessage("y") {
+		fmt.Println(msg.Content)
+	}
+
+	return notifier.Notify(agent.Report())
+}
@@ -2,23 +2,48 @@ package main
 
 import (
 	"cloud-assist/internal/auth"
+	"cloud-assist/internal/docker"
+	"cloud-assist/internal/history"
 	"cloud-assist/ui"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
 )
 
+func init() {
+	auth.SetPassphrasePrompt(promptCredentialPassphrase)
+}
+
+// promptCredentialPassphrase asks for the encrypted-credential-store
+// passphrase directly on the terminal, before the Bubble Tea program takes
+// over stdin, rather than nesting a second Bubble Tea program (ui.PasswordModel)
+// inside the one already running.
+func promptCredentialPassphrase() (string, error) {
+	fmt.Print("Enter passphrase to unlock stored credentials: ")
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // AppModel is the main application model
 type AppModel struct {
 	currentScreen string
 	loginModel    tea.Model
-	chatModel     ui.ChatModel
 	textInput     ui.TextInputModel
 	multiline     ui.MultilineModel
 	Select        ui.SelectModel
 	confirmation  ui.ConfirmationModel
+	conversations ui.ConversationsModel
 	statusBar     ui.StatusBarModel
+	historyStore  *history.Store
+	buffers       []ui.Buffer
+	activeBuffer  int
 	width         int
 	height        int
 	authenticated bool
@@ -32,25 +57,54 @@ type showScreenMsg struct {
 
 // App state constants
 const (
-	screenLogin        = "login"
-	screenChat         = "chat"
-	screenTextInput    = "textInput"
-	screenMultiline    = "multiline"
-	screenSelect       = "select"
-	screenConfirmation = "confirmation"
+	screenLogin         = "login"
+	screenBuffers       = "buffers"
+	screenTextInput     = "textInput"
+	screenMultiline     = "multiline"
+	screenSelect        = "select"
+	screenConfirmation  = "confirmation"
+	screenConversations = "conversations"
 )
 
+// chatBuffer returns the chat buffer among m.buffers, which is always
+// present (see NewAppModel).
+func (m AppModel) chatBuffer() *ui.ChatBuffer {
+	for _, b := range m.buffers {
+		if chat, ok := b.(*ui.ChatBuffer); ok {
+			return chat
+		}
+	}
+	return nil
+}
+
+// bufferTabs renders a one-line tab bar naming each buffer, highlighting
+// the active one.
+func (m AppModel) bufferTabs() string {
+	tabs := make([]string, len(m.buffers))
+	for i, b := range m.buffers {
+		name := b.Name()
+		if i == m.activeBuffer {
+			name = "[" + name + "]"
+		}
+		tabs[i] = name
+	}
+	return strings.Join(tabs, "  ")
+}
+
 // NewAppModel creates a new application model
 func NewAppModel() AppModel {
 	// Create default initial models for all components
 	loginModel := InitLoginModel()
 	chatModel := ui.NewChatModel(100, 40)
+	if dockerService, err := docker.NewDockerService(); err == nil {
+		chatModel.SetCompletionProvider(newDockerCompletionProvider(dockerService))
+	}
 	textInput := ui.NewTextInput("Sample Text Input", "Type something...", 30)
 	multiline := ui.NewMultiline("Sample Multiline", "Type multiple lines...", 40, 10)
 
 	// Create select items
 	selectItems := []ui.SelectItem{
-		{Title: "Chat Interface", Description: "Shows the chat UI", Value: screenChat},
+		{Title: "Chat Interface", Description: "Shows the chat UI", Value: screenBuffers},
 		{Title: "Text Input", Description: "Shows a text input component", Value: screenTextInput},
 		{Title: "Multiline Editor", Description: "Shows a multiline text editor", Value: screenMultiline},
 		{Title: "Confirmation Dialog", Description: "Shows a confirmation dialog", Value: screenConfirmation},
@@ -63,26 +117,59 @@ func NewAppModel() AppModel {
 	// Create status bar
 	statusBar := ui.NewStatusBar(100)
 
+	// Create the conversation history store; if it can't be created (e.g. no
+	// writable config dir) conversations simply aren't persisted.
+	historyStore, err := history.NewStore()
+	if err != nil {
+		fmt.Println("Warning: conversation history disabled:", err)
+	}
+	conversationsModel := ui.NewConversationsModel(historyStore, 60, 20)
+
+	// Buffers: the main authenticated UI is an ordered list of panels the
+	// user cycles through with ctrl+n/ctrl+p instead of a hard-coded screen.
+	buffers := []ui.Buffer{
+		ui.NewChatBuffer(chatModel, 100, 30),
+		ui.NewStatusBuffer(100, 20),
+		ui.NewProfileBuffer(100, 20),
+	}
+
 	// Check if user is already authenticated
 	initialScreen := screenLogin
 	authenticated := false
-	if _, err := auth.GetAPIKey(); err == nil {
-		// API key exists, skip login and go directly to chat
-		initialScreen = screenChat
+	if apiKey, err := auth.GetAPIKey(auth.DefaultProfile); err == nil {
+		// API key exists, skip login and go directly to the buffer UI
+		initialScreen = screenBuffers
 		authenticated = true
+		for _, b := range buffers {
+			if profile, ok := b.(*ui.ProfileBuffer); ok {
+				profile.SetAPIKey(apiKey)
+			}
+		}
 	}
 
-	return AppModel{
+	app := AppModel{
 		currentScreen: initialScreen,
 		authenticated: authenticated,
 		loginModel:    loginModel,
-		chatModel:     chatModel,
 		textInput:     textInput,
 		multiline:     multiline,
 		Select:        selectModel,
 		confirmation:  confirmationModel,
+		conversations: conversationsModel,
 		statusBar:     statusBar,
+		historyStore:  historyStore,
+		buffers:       buffers,
+	}
+
+	if historyStore != nil {
+		if conv, err := historyStore.NewConversation(); err == nil {
+			if chat := app.chatBuffer(); chat != nil {
+				chat.Chat().SetConversation(historyStore, conv.ID)
+			}
+		}
 	}
+
+	return app
 }
 
 // InitLoginModel creates a new login model
@@ -95,14 +182,18 @@ func (m AppModel) Init() tea.Cmd {
 	// Initialize all models to ensure they're ready for use
 	cmds := []tea.Cmd{
 		m.loginModel.Init(),
-		m.chatModel.Init(),
 		m.textInput.Init(),
 		m.multiline.Init(),
 		m.Select.Init(),
 		m.confirmation.Init(),
+		m.conversations.Init(),
 		m.statusBar.Init(),
 	}
 
+	for _, b := range m.buffers {
+		cmds = append(cmds, b.Init())
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -121,7 +212,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case authSuccessMsg:
 		m.authenticated = true
-		m.currentScreen = screenChat // Go directly to chat interface in production
+		m.currentScreen = screenBuffers // Go directly to the buffer UI in production
 		// Update status bar mode and status
 		m.statusBar.SetMode("chat")
 		m.statusBar.SetStatus("cloud-assist ready")
@@ -134,6 +225,9 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	handled := true
+
 	// Handle updates based on current screen
 	switch m.currentScreen {
 	case screenLogin:
@@ -145,14 +239,21 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			{Key: "ctrl+c", Description: "quit"},
 		})
 
-		newLoginModel, loginCmd := m.loginModel.Update(msg)
-		m.loginModel = newLoginModel
+		if isKey {
+			loginModel, ok := m.loginModel.(ui.LoginModel)
+			if ok {
+				handled, cmd = loginModel.HandleInput(keyMsg)
+				m.loginModel = loginModel
+			}
+		} else {
+			m.loginModel, cmd = m.loginModel.Update(msg)
+		}
 
 		// Check if login was successful
 		if loginModel, ok := m.loginModel.(ui.LoginModel); ok && loginModel.Authenticated() {
 			// Save API key securely
 			apiKey := loginModel.GetAPIKey()
-			err := auth.SaveAPIKey(apiKey)
+			err := auth.SaveAPIKey(auth.DefaultProfile, apiKey)
 			if err != nil {
 				fmt.Println("Error saving API key:", err)
 			}
@@ -160,8 +261,6 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg { return authSuccessMsg{} }
 		}
 
-		cmd = loginCmd
-
 	case screenSelect:
 		// Set status bar for select screen
 		m.statusBar.SetMode("select")
@@ -172,8 +271,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			{Key: "ctrl+c", Description: "quit"},
 		})
 
-		newSelectModel, selectCmd := m.Select.Update(msg)
-		m.Select = newSelectModel
+		if isKey {
+			handled, cmd = m.Select.HandleInput(keyMsg)
+		} else {
+			m.Select, cmd = m.Select.Update(msg)
+		}
 
 		// Check if an item was selected
 		if selected := m.Select.Selected(); selected != nil {
@@ -183,23 +285,79 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		cmd = selectCmd
-
-	case screenChat:
-		// Set status bar for chat screen
-		m.statusBar.SetMode("chat")
-		m.statusBar.SetStatus("cloud-assist ready")
+	case screenBuffers:
+		// Set status bar for the buffer UI
+		active := m.buffers[m.activeBuffer]
+		m.statusBar.SetMode(active.Name())
+		chat := m.chatBuffer()
+		switch {
+		case chat != nil && chat.Chat().Waiting():
+			m.statusBar.SetStatus(chat.Chat().SpinnerView() + " waiting for reply...")
+		case chat != nil:
+			if attempt, reconnecting := chat.Chat().Reconnecting(); reconnecting {
+				m.statusBar.SetStatus(fmt.Sprintf("reconnecting to agent (attempt %d)...", attempt))
+			} else {
+				m.statusBar.SetStatus("cloud-assist ready")
+			}
+		default:
+			m.statusBar.SetStatus("cloud-assist ready")
+		}
+		if chat != nil {
+			if branch, ok := chat.Chat().Branch(); ok {
+				m.statusBar.SetBranch(branch)
+			}
+		}
 		m.statusBar.SetKeyBindings([]ui.KeyBinding{
-			{Key: "enter", Description: "send"},
+			{Key: "ctrl+n/p", Description: "switch buffer"},
+			{Key: "ctrl+l", Description: "toggle log"},
+			{Key: "ctrl+o", Description: "conversations"},
 			{Key: "esc", Description: "back"},
 			{Key: "ctrl+c", Description: "quit"},
 		})
 
-		newChatModel, chatCmd := m.chatModel.Update(msg)
-		if updatedModel, ok := newChatModel.(ui.ChatModel); ok {
-			m.chatModel = updatedModel
+		if isKey {
+			switch keyMsg.String() {
+			case "ctrl+n":
+				m.activeBuffer = (m.activeBuffer + 1) % len(m.buffers)
+			case "ctrl+p":
+				m.activeBuffer = (m.activeBuffer - 1 + len(m.buffers)) % len(m.buffers)
+			case "ctrl+l":
+				active.LogToFile(!active.Persisting())
+			case "ctrl+o":
+				if m.historyStore != nil {
+					m.conversations.Refresh()
+				}
+				return m, func() tea.Msg { return showScreenMsg{screen: screenConversations} }
+			default:
+				handled, cmd = active.HandleInput(keyMsg)
+			}
+		} else {
+			m.buffers[m.activeBuffer], cmd = active.Update(msg)
+		}
+
+	case screenConversations:
+		// Set status bar for conversations screen
+		m.statusBar.SetMode("conversations")
+		m.statusBar.SetStatus("browse past sessions")
+		m.statusBar.SetKeyBindings([]ui.KeyBinding{
+			{Key: "↑/↓", Description: "navigate"},
+			{Key: "enter", Description: "reopen"},
+			{Key: "esc", Description: "back"},
+		})
+
+		if isKey {
+			handled, cmd = m.conversations.HandleInput(keyMsg)
+		} else {
+			m.conversations, cmd = m.conversations.Update(msg)
+		}
+
+		if conv := m.conversations.Selected(); conv != nil {
+			m.conversations.ClearSelection()
+			if chat := m.chatBuffer(); chat != nil {
+				chat.Chat().SetConversation(m.historyStore, conv.ID)
+			}
+			return m, func() tea.Msg { return showScreenMsg{screen: screenBuffers} }
 		}
-		cmd = chatCmd
 
 	case screenTextInput:
 		// Set status bar for text input screen
@@ -210,9 +368,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			{Key: "esc", Description: "back"},
 		})
 
-		newTextInput, textInputCmd := m.textInput.Update(msg)
-		m.textInput = newTextInput
-		cmd = textInputCmd
+		if isKey {
+			handled, cmd = m.textInput.HandleInput(keyMsg)
+		} else {
+			m.textInput, cmd = m.textInput.Update(msg)
+		}
 
 	case screenMultiline:
 		// Set status bar for multiline screen
@@ -223,9 +383,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			{Key: "esc", Description: "back"},
 		})
 
-		newMultiline, multilineCmd := m.multiline.Update(msg)
-		m.multiline = newMultiline
-		cmd = multilineCmd
+		if isKey {
+			handled, cmd = m.multiline.HandleInput(keyMsg)
+		} else {
+			m.multiline, cmd = m.multiline.Update(msg)
+		}
 
 	case screenConfirmation:
 		// Set status bar for confirmation screen
@@ -236,8 +398,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			{Key: "enter", Description: "select"},
 		})
 
-		newConfirmation, confirmationCmd := m.confirmation.Update(msg)
-		m.confirmation = newConfirmation
+		if isKey {
+			handled, cmd = m.confirmation.HandleInput(keyMsg)
+		} else {
+			m.confirmation, cmd = m.confirmation.Update(msg)
+		}
 
 		// Check if confirmation dialog has a result
 		if m.confirmation.HasResult() {
@@ -247,20 +412,23 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			} else {
 				// Return to chat screen if canceled
-				return m, func() tea.Msg { return showScreenMsg{screen: screenChat} }
+				return m, func() tea.Msg { return showScreenMsg{screen: screenBuffers} }
 			}
 		}
-
-		cmd = confirmationCmd
 	}
 
-	// Handle escape key globally
-	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" && m.currentScreen != screenLogin {
-		if m.currentScreen != screenChat {
-			// Go back to chat screen from any other screen (except login)
-			return m, func() tea.Msg { return showScreenMsg{screen: screenChat} }
+	// Global key bindings only run when the focused sub-model didn't
+	// consume the key itself.
+	if isKey && !handled {
+		switch keyMsg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.currentScreen != screenLogin && m.currentScreen != screenBuffers {
+				// Go back to chat screen from any other screen (except login)
+				return m, func() tea.Msg { return showScreenMsg{screen: screenBuffers} }
+			}
 		}
-		// In chat screen, ESC does nothing special
 	}
 
 	cmds = append(cmds, cmd)
@@ -276,14 +444,16 @@ func (m AppModel) View() string {
 		content = m.loginModel.View()
 	case screenSelect:
 		content = "Cloud-Assist CLI\n\nSelect a component to view:\n\n" + m.Select.View() + "\n\nPress ESC to return to this menu from any component."
-	case screenChat:
-		content = m.chatModel.View()
+	case screenBuffers:
+		content = m.bufferTabs() + "\n" + m.buffers[m.activeBuffer].View()
 	case screenTextInput:
 		content = "Text Input Demo\n\n" + m.textInput.View() + "\n\nCurrent value: " + m.textInput.Value() + "\n\nPress ESC to go back."
 	case screenMultiline:
 		content = "Multiline Editor Demo\n\n" + m.multiline.View() + "\n\nCurrent content:\n" + m.multiline.Value() + "\n\nPress ESC to go back."
 	case screenConfirmation:
 		content = "Confirmation Dialog Demo\n\n" + m.confirmation.View()
+	case screenConversations:
+		content = "Past Conversations\n\n" + m.conversations.View() + "\n\nPress Enter to reopen, ESC to return to chat."
 	default:
 		content = "Unknown screen"
 	}
@@ -293,8 +463,25 @@ func (m AppModel) View() string {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "record" {
+		if err := runRecord(os.Args[2:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	flags := parseHeadlessFlags()
+	if flags.headless {
+		if err := runHeadless(flags); err != nil {
+			fmt.Printf("Error running headless session: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Check if we have a saved API key
-	apiKey, err := auth.GetAPIKey()
+	apiKey, err := auth.GetAPIKey(auth.DefaultProfile)
 	if err != nil {
 		fmt.Println("Starting with login screen...")
 	} else {
@@ -303,6 +490,7 @@ func main() {
 
 	app := NewAppModel()
 	p := tea.NewProgram(app, tea.WithAltScreen())
+	ui.SetProgram(p)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"cloud-assist/internal/docker"
+)
+
+// runRecord implements the "cloud-assist record" subcommand: it drives
+// docker.Recorder against a real Docker daemon for each command in args
+// (joined back together and split on ";", so
+// "record --out fixtures/dev.json -- docker ps ; docker logs app" runs
+// both "docker ps" and "docker logs app"), then writes the resulting
+// cassette to --out for mock.DockerCommandService.LoadCassette to
+// replay later.
+func runRecord(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	out := fs.String("out", "fixtures/dev.json", "path to write the recorded cassette")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	commands := splitCommands(fs.Args())
+	if len(commands) == 0 {
+		return fmt.Errorf("record: no commands given, e.g. cloud-assist record --out fixtures/dev.json -- docker ps ; docker logs app")
+	}
+
+	service, err := docker.NewDockerService()
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	recorder := docker.NewRecorder(service)
+
+	for _, command := range commands {
+		if _, err := recorder.ExecuteCommand(command); err != nil {
+			fmt.Printf("record: %s: %v\n", command, err)
+		}
+	}
+
+	if err := recorder.Save(*out); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	fmt.Printf("Recorded %d commands to %s\n", len(commands), *out)
+	return nil
+}
+
+// splitCommands rejoins the positional arguments left after flag parsing
+// and splits them on ";", trimming whitespace around each command.
+func splitCommands(args []string) []string {
+	var commands []string
+	for _, command := range strings.Split(strings.Join(args, " "), ";") {
+		command = strings.TrimSpace(command)
+		if command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
@@ -7,57 +7,81 @@ import (
 	"path/filepath"
 )
 
-var apiKeyFileName = ".cloud-assist-api-key"
+// legacyAPIKeyFileName is the plaintext file this package used before the
+// Keystore migration. GetAPIKey imports it into the keystore on first read
+// and removes it, so upgrading doesn't silently drop a saved key.
+var legacyAPIKeyFileName = ".cloud-assist-api-key"
 
-// SaveAPIKey saves the API key to a file in the user's home directory
-func SaveAPIKey(apiKey string) error {
+// SaveAPIKey saves the API key for profile to the keystore (OS keychain,
+// falling back to an encrypted file).
+func SaveAPIKey(profile, apiKey string) error {
 	if apiKey == "" {
 		return errors.New("API key cannot be empty")
 	}
-
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+	if err := keystore().Set(profile, apiKey); err != nil {
+		return err
 	}
-
-	keyPath := filepath.Join(homeDir, apiKeyFileName)
-	return os.WriteFile(keyPath, []byte(apiKey), 0600) // Read/write permissions for user only
+	return recordProfile(profile)
 }
 
-// GetAPIKey retrieves the API key from the user's home directory
-func GetAPIKey() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+// GetAPIKey retrieves the API key for profile from the keystore. On first
+// call for DefaultProfile, it migrates a key left over from the old
+// plaintext file into the keystore and deletes the file.
+func GetAPIKey(profile string) (string, error) {
+	if apiKey, err := migrateLegacyKey(profile); err != nil {
+		return "", err
+	} else if apiKey != "" {
+		return apiKey, nil
 	}
 
-	keyPath := filepath.Join(homeDir, apiKeyFileName)
-	data, err := os.ReadFile(keyPath)
+	apiKey, err := keystore().Get(profile)
 	if err != nil {
 		return "", fmt.Errorf("failed to read API key: %w", err)
 	}
-
-	apiKey := string(data)
 	if apiKey == "" {
 		return "", errors.New("stored API key is empty")
 	}
-
 	return apiKey, nil
 }
 
-// ClearAPIKey removes the stored API key
-func ClearAPIKey() error {
+// ClearAPIKey removes the stored API key for profile.
+func ClearAPIKey(profile string) error {
+	return keystore().Delete(profile)
+}
+
+// migrateLegacyKey imports the plaintext ~/.cloud-assist-api-key file into
+// the keystore under profile and removes it, returning the imported key so
+// the caller's GetAPIKey doesn't need a second round trip through the
+// keystore. It is a no-op once the legacy file is gone.
+func migrateLegacyKey(profile string) (string, error) {
+	if profile != DefaultProfile {
+		return "", nil
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	keyPath := filepath.Join(homeDir, apiKeyFileName)
-	_, err = os.Stat(keyPath)
+	path := filepath.Join(homeDir, legacyAPIKeyFileName)
+	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
-		// File doesn't exist, nothing to do
-		return nil
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read legacy API key file: %w", err)
 	}
 
-	return os.Remove(keyPath)
+	apiKey := string(data)
+	if apiKey == "" {
+		os.Remove(path)
+		return "", nil
+	}
+
+	if err := keystore().Set(profile, apiKey); err != nil {
+		return "", fmt.Errorf("failed to migrate legacy API key: %w", err)
+	}
+	recordProfile(profile)
+	os.Remove(path)
+	return apiKey, nil
 }
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+var credentialsFileName = ".cloud-assist-credentials.enc"
+
+// PassphrasePrompt is called by the encrypted-file keystore the first time
+// it needs a passphrase to derive its encryption key. It is nil until the
+// UI layer installs one via SetPassphrasePrompt; internal/auth can't import
+// ui directly (ui already imports auth), so the prompt is wired the same
+// way AgentClient's reconnect handler is - a callback set from outside.
+var PassphrasePrompt func() (string, error)
+
+// SetPassphrasePrompt installs the callback the encrypted-file keystore
+// uses to ask for its passphrase, e.g. one backed by ui.PasswordModel.
+func SetPassphrasePrompt(prompt func() (string, error)) {
+	PassphrasePrompt = prompt
+}
+
+// encryptedFile is the on-disk format: one scrypt salt shared by every
+// profile, and a nonce+ciphertext per profile so adding a profile doesn't
+// require re-encrypting the others.
+type encryptedFile struct {
+	Salt    string            `json:"salt"`
+	Entries map[string]string `json:"entries"` // profile -> base64(nonce || ciphertext)
+}
+
+// encryptedFileKeystore is the fallback used when the OS has no keychain
+// available. The encryption key is derived from a user-supplied passphrase
+// via scrypt on each Get/Set; the passphrase is cached in memory for the
+// process lifetime so it is only prompted for once.
+type encryptedFileKeystore struct {
+	mu         sync.Mutex
+	passphrase []byte
+}
+
+func newEncryptedFileKeystore() *encryptedFileKeystore {
+	return &encryptedFileKeystore{}
+}
+
+func (k *encryptedFileKeystore) Get(profile string) (string, error) {
+	file, err := k.load()
+	if err != nil {
+		return "", err
+	}
+
+	encoded, ok := file.Entries[profile]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	key, err := k.deriveKey(file.Salt)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode stored credential: %w", err)
+	}
+	if len(raw) < 24 {
+		return "", errors.New("stored credential is corrupt")
+	}
+
+	var nonce [24]byte
+	copy(nonce[:], raw[:24])
+
+	plain, ok := secretbox.Open(nil, raw[24:], &nonce, key)
+	if !ok {
+		return "", errors.New("failed to decrypt credential: wrong passphrase or corrupt file")
+	}
+	return string(plain), nil
+}
+
+func (k *encryptedFileKeystore) Set(profile, apiKey string) error {
+	file, err := k.loadOrCreate()
+	if err != nil {
+		return err
+	}
+
+	key, err := k.deriveKey(file.Salt)
+	if err != nil {
+		return err
+	}
+
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := secretbox.Seal(nonce[:], []byte(apiKey), &nonce, key)
+	file.Entries[profile] = base64.StdEncoding.EncodeToString(sealed)
+
+	return k.save(file)
+}
+
+func (k *encryptedFileKeystore) Delete(profile string) error {
+	file, err := k.load()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	delete(file.Entries, profile)
+	return k.save(file)
+}
+
+func (k *encryptedFileKeystore) path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, credentialsFileName), nil
+}
+
+func (k *encryptedFileKeystore) load() (*encryptedFile, error) {
+	path, err := k.path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	if file.Entries == nil {
+		file.Entries = map[string]string{}
+	}
+	return &file, nil
+}
+
+func (k *encryptedFileKeystore) loadOrCreate() (*encryptedFile, error) {
+	file, err := k.load()
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return &encryptedFile{
+		Salt:    base64.StdEncoding.EncodeToString(salt),
+		Entries: map[string]string{},
+	}, nil
+}
+
+func (k *encryptedFileKeystore) save(file *encryptedFile) error {
+	path, err := k.path()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials file: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// deriveKey turns the cached (or freshly prompted) passphrase and the
+// file's stored salt into a secretbox key via scrypt.
+func (k *encryptedFileKeystore) deriveKey(encodedSalt string) (*[32]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.passphrase == nil {
+		if PassphrasePrompt == nil {
+			return nil, errors.New("no keychain available and no passphrase prompt installed")
+		}
+		passphrase, err := PassphrasePrompt()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		k.passphrase = []byte(passphrase)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode salt: %w", err)
+	}
+
+	derived, err := scrypt.Key(k.passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+
+	var key [32]byte
+	copy(key[:], derived)
+	return &key, nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrKeyNotFound is returned when no credential is stored for a profile in
+// either the OS keychain or the encrypted file fallback.
+var ErrKeyNotFound = errors.New("no credential stored for this profile")
+
+// DefaultProfile is the profile used by callers that don't manage multiple
+// named credentials (staging/prod, etc.).
+const DefaultProfile = "default"
+
+const keyringService = "cloud-assist"
+
+// Keystore persists API keys per named profile. keyringKeystore is the
+// production implementation; tests can substitute their own.
+type Keystore interface {
+	Get(profile string) (string, error)
+	Set(profile, apiKey string) error
+	Delete(profile string) error
+}
+
+var defaultKeystore Keystore
+
+// keystore returns the process-wide Keystore, building it on first use.
+func keystore() Keystore {
+	if defaultKeystore == nil {
+		defaultKeystore = &keyringKeystore{fallback: newEncryptedFileKeystore()}
+	}
+	return defaultKeystore
+}
+
+// keyringKeystore stores credentials in the OS keychain (macOS Keychain,
+// Windows Credential Manager, libsecret/KWallet on Linux) and falls back to
+// an encrypted file when the platform has none of those available, e.g. a
+// headless Linux box with no secret service running.
+type keyringKeystore struct {
+	fallback Keystore
+}
+
+func (k *keyringKeystore) Get(profile string) (string, error) {
+	apiKey, err := keyring.Get(keyringService, profile)
+	switch {
+	case err == nil:
+		return apiKey, nil
+	case errors.Is(err, keyring.ErrNotFound):
+		return k.fallback.Get(profile)
+	default:
+		// No keychain backend on this platform - fall back silently rather
+		// than surfacing an OS integration error to the user.
+		return k.fallback.Get(profile)
+	}
+}
+
+func (k *keyringKeystore) Set(profile, apiKey string) error {
+	if err := keyring.Set(keyringService, profile, apiKey); err != nil {
+		return k.fallback.Set(profile, apiKey)
+	}
+	return nil
+}
+
+func (k *keyringKeystore) Delete(profile string) error {
+	err := keyring.Delete(keyringService, profile)
+	if err == nil || errors.Is(err, keyring.ErrNotFound) {
+		return k.fallback.Delete(profile)
+	}
+	return k.fallback.Delete(profile)
+}
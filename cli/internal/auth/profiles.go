@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// profilesFileName tracks which profile names have been configured. The
+// keychain itself has no enumeration API, so this is the only record of
+// "which profiles exist" a picker can read.
+var profilesFileName = ".cloud-assist-profiles"
+
+// ListProfiles returns every profile name previously saved via SaveAPIKey,
+// DefaultProfile first if present.
+func ListProfiles() ([]string, error) {
+	profiles, err := loadProfileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]string, 0, len(profiles))
+	if profiles[DefaultProfile] {
+		ordered = append(ordered, DefaultProfile)
+	}
+	for name := range profiles {
+		if name != DefaultProfile {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered, nil
+}
+
+// recordProfile adds profile to the known-profiles file, a no-op if it's
+// already recorded.
+func recordProfile(profile string) error {
+	profiles, err := loadProfileNames()
+	if err != nil {
+		return err
+	}
+	if profiles[profile] {
+		return nil
+	}
+
+	profiles[profile] = true
+	return saveProfileNames(profiles)
+}
+
+func profilesPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, profilesFileName), nil
+}
+
+func loadProfileNames() (map[string]bool, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+
+	profiles := make(map[string]bool, len(names))
+	for _, name := range names {
+		profiles[name] = true
+	}
+	return profiles, nil
+}
+
+func saveProfileNames(profiles map[string]bool) error {
+	path, err := profilesPath()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+
+	data, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("failed to encode profiles file: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var toolPolicyFileName = ".cloud-assist-tool-policies"
+
+// ToolPolicy records the user's standing decision for a tool, keyed by
+// name, so a repeated safe read (e.g. read_file) doesn't keep prompting.
+type ToolPolicy string
+
+const (
+	PolicyAlways ToolPolicy = "always"
+	PolicyNever  ToolPolicy = "never"
+	PolicyAsk    ToolPolicy = "ask"
+)
+
+// LoadToolPolicies reads the per-tool approval policies saved alongside
+// the API key file. A missing file is not an error: it means every tool
+// still defaults to PolicyAsk.
+func LoadToolPolicies() (map[string]ToolPolicy, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, toolPolicyFileName))
+	if os.IsNotExist(err) {
+		return map[string]ToolPolicy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tool policies: %w", err)
+	}
+
+	policies := map[string]ToolPolicy{}
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse tool policies: %w", err)
+	}
+	return policies, nil
+}
+
+// SaveToolPolicies persists the per-tool approval policies to the user's
+// home directory with the same permissions as the API key file.
+func SaveToolPolicies(policies map[string]ToolPolicy) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	data, err := json.Marshal(policies)
+	if err != nil {
+		return fmt.Errorf("failed to encode tool policies: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(homeDir, toolPolicyFileName), data, 0600)
+}
@@ -0,0 +1,278 @@
+// Package conversation persists chat turns as a tree on disk, the way
+// lmcli's new/reply/view/rm commands do, so editing an earlier message
+// and re-prompting forks a new branch instead of discarding history.
+package conversation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Node is a single persisted turn: a user prompt, agent reply, or tool
+// call/result, linked to its parent so forking from an earlier node
+// doesn't disturb the branch it forked from.
+type Node struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Role      string    `json:"role"` // "user", "agent", "tool_call", "tool_result"
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tree is one persisted conversation: a set of nodes forming a tree
+// (branches share a common prefix of ancestors), plus the ID of the
+// most recently appended node, i.e. the active branch's tip.
+type Tree struct {
+	ID        string           `json:"id"`
+	Title     string           `json:"title"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Nodes     map[string]*Node `json:"nodes"`
+	HeadID    string           `json:"head_id,omitempty"`
+}
+
+// Store is a JSON-file-backed collection of conversation trees, one file
+// per conversation under the user's config directory.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore creates a store rooted at "<user config dir>/cloud-assist/conversation-trees".
+func NewStore() (*Store, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user config directory: %w", err)
+	}
+
+	dir := filepath.Join(configDir, "cloud-assist", "conversation-trees")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create conversation-trees directory: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// New creates and persists an empty conversation tree, deriving its ID
+// from the current time so trees sort chronologically.
+func (s *Store) New() (*Tree, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	tree := &Tree{
+		ID:        now.Format("20060102T150405.000000000"),
+		Title:     "New conversation",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Nodes:     map[string]*Node{},
+	}
+
+	return tree, s.save(tree)
+}
+
+// Load loads a conversation tree by ID.
+func (s *Store) Load(id string) (*Tree, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadLocked(id)
+}
+
+// List returns all conversation trees, most recently updated first.
+func (s *Store) List() ([]Tree, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversation-trees directory: %w", err)
+	}
+
+	var trees []Tree
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		tree, err := s.loadLocked(id)
+		if err != nil {
+			continue
+		}
+		trees = append(trees, *tree)
+	}
+
+	sortTreesByRecency(trees)
+	return trees, nil
+}
+
+// Delete removes a conversation tree permanently.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete conversation tree %s: %w", id, err)
+	}
+	return nil
+}
+
+// Append adds a node as a child of parentID (empty for the first node in
+// the tree), advances HeadID to it, and derives the tree's title from
+// the first user message if one hasn't been set yet. This is also how a
+// fork is created: passing an earlier nodeID as parentID starts a new
+// branch under it rather than continuing whatever was appended after it.
+func (s *Store) Append(treeID, parentID, role, content string) (*Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tree, err := s.loadLocked(treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != "" {
+		if _, ok := tree.Nodes[parentID]; !ok {
+			return nil, fmt.Errorf("parent node %s not found in conversation %s", parentID, treeID)
+		}
+	}
+
+	now := time.Now()
+	node := &Node{
+		ID:        now.Format("20060102T150405.000000000"),
+		ParentID:  parentID,
+		Role:      role,
+		Content:   content,
+		CreatedAt: now,
+	}
+	tree.Nodes[node.ID] = node
+	tree.HeadID = node.ID
+	tree.UpdatedAt = now
+
+	if tree.Title == "" || tree.Title == "New conversation" {
+		if role == "user" {
+			tree.Title = deriveTitle(content)
+		}
+	}
+
+	if err := s.save(tree); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// AncestorChain walks parent links from nodeID back to the root,
+// returning nodes root-first so callers can replay a branch in order.
+func (s *Store) AncestorChain(treeID, nodeID string) ([]*Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tree, err := s.loadLocked(treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []*Node
+	for id := nodeID; id != ""; {
+		node, ok := tree.Nodes[id]
+		if !ok {
+			return nil, fmt.Errorf("node %s not found in conversation %s", id, treeID)
+		}
+		chain = append(chain, node)
+		id = node.ParentID
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// Branches returns the tip node of every leaf branch (a node with no
+// children), most recently created first, so a picker can list each
+// branch by its latest turn.
+func (s *Store) Branches(treeID string) ([]*Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tree, err := s.loadLocked(treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasChild := map[string]bool{}
+	for _, node := range tree.Nodes {
+		if node.ParentID != "" {
+			hasChild[node.ParentID] = true
+		}
+	}
+
+	var tips []*Node
+	for _, node := range tree.Nodes {
+		if !hasChild[node.ID] {
+			tips = append(tips, node)
+		}
+	}
+
+	for i := 1; i < len(tips); i++ {
+		for j := i; j > 0 && tips[j].CreatedAt.After(tips[j-1].CreatedAt); j-- {
+			tips[j], tips[j-1] = tips[j-1], tips[j]
+		}
+	}
+	return tips, nil
+}
+
+func (s *Store) loadLocked(id string) (*Tree, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation tree %s: %w", id, err)
+	}
+
+	var tree Tree
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation tree %s: %w", id, err)
+	}
+	if tree.Nodes == nil {
+		tree.Nodes = map[string]*Node{}
+	}
+
+	return &tree, nil
+}
+
+func (s *Store) save(tree *Tree) error {
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation tree %s: %w", tree.ID, err)
+	}
+
+	return os.WriteFile(s.path(tree.ID), data, 0600)
+}
+
+func sortTreesByRecency(trees []Tree) {
+	for i := 1; i < len(trees); i++ {
+		for j := i; j > 0 && trees[j].UpdatedAt.After(trees[j-1].UpdatedAt); j-- {
+			trees[j], trees[j-1] = trees[j-1], trees[j]
+		}
+	}
+}
+
+// deriveTitle builds a short title from the first user message.
+func deriveTitle(content string) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\n", " "))
+	const maxLen = 48
+	if len(content) <= maxLen {
+		return content
+	}
+	return content[:maxLen] + "…"
+}
@@ -0,0 +1,146 @@
+// Package docker provides a production CommandService backed by the real
+// Docker Engine API, so the TUI can be wired to an actual daemon instead
+// of mock.DockerCommandService's canned fixtures without any other code
+// changing.
+package docker
+
+import (
+	"fmt"
+
+	dockererrdefs "github.com/docker/docker/errdefs"
+
+	"cloud-assist/internal/errdefs"
+	"cloud-assist/internal/mock"
+)
+
+// CommandService is the command-in, output-out surface
+// mock.DockerCommandService implements for local UI development:
+// ExecuteCommand runs a typed command line and returns its formatted
+// output, SuggestNextCommand proposes what to run next given the last
+// one. Extracting it lets callers construct the TUI against either the
+// mock or Service interchangeably.
+type CommandService interface {
+	ExecuteCommand(command string) (string, error)
+	SuggestNextCommand(previousCommand string) string
+}
+
+// Service implements CommandService against a real Docker daemon. It
+// recovers the Capability a typed command line encodes via runtime.Parse
+// and dispatches to the matching ContainerBackend call - the same intent
+// detection AgentService.dispatchCommand uses - exposed here as a
+// standalone command-in-output-out service rather than one buried in
+// AgentService's conversational flow.
+type Service struct {
+	runtime mock.ContainerRuntime
+}
+
+var _ CommandService = (*Service)(nil)
+var _ CommandService = (*mock.DockerCommandService)(nil)
+
+// NewService wraps runtime (e.g. a *mock.DockerRuntime connected to the
+// local daemon or DOCKER_HOST) as a CommandService.
+func NewService(runtime mock.ContainerRuntime) *Service {
+	return &Service{runtime: runtime}
+}
+
+// NewDockerService connects to the Docker daemon described by the
+// standard DOCKER_HOST / DOCKER_* environment variables and wraps it as a
+// CommandService.
+func NewDockerService() (*Service, error) {
+	runtime, err := mock.NewDockerRuntime()
+	if err != nil {
+		return nil, err
+	}
+	return NewService(runtime), nil
+}
+
+// ExecuteCommand parses command into a Capability via runtime.Parse and
+// dispatches to the matching ContainerBackend call, returning the
+// runtime's output as it comes: for Docker, tab-separated tables with
+// fewer columns than mock.DockerCommandService's space-aligned fixtures,
+// not reformatted into the fixtures' table/JSON shape. Any error the
+// daemon returns is reclassified into an errdefs kind via classifyError
+// so the UI can tell "no such container" from "daemon unreachable"
+// without string-matching the Docker Engine API's error text.
+func (s *Service) ExecuteCommand(command string) (string, error) {
+	capability, args, ok := s.runtime.Parse(command)
+	if !ok {
+		return "", fmt.Errorf("unknown command: %s", command)
+	}
+
+	switch capability {
+	case mock.CapabilityListContainers:
+		out, err := s.runtime.ListContainers(false)
+		return out, classifyError(err, "", "")
+	case mock.CapabilityListAllContainers:
+		out, err := s.runtime.ListContainers(true)
+		return out, classifyError(err, "", "")
+	case mock.CapabilityImages:
+		out, err := s.runtime.Images()
+		return out, classifyError(err, "", "")
+	case mock.CapabilityNetworkList:
+		out, err := s.runtime.ListNetworks()
+		return out, classifyError(err, "", "")
+	case mock.CapabilityLogs:
+		out, err := s.runtime.Logs(args[0])
+		return out, classifyError(err, "container", args[0])
+	case mock.CapabilityStart:
+		out, err := s.runtime.Start(args[0])
+		return out, classifyError(err, "container", args[0])
+	case mock.CapabilityRestart:
+		out, err := s.runtime.Restart(args[0])
+		return out, classifyError(err, "container", args[0])
+	case mock.CapabilityStop:
+		out, err := s.runtime.Stop(args[0])
+		return out, classifyError(err, "container", args[0])
+	case mock.CapabilityNetworkInspect:
+		out, err := s.runtime.InspectNetwork(args[0])
+		return out, classifyError(err, "network", args[0])
+	case mock.CapabilityNetworkConnect:
+		out, err := s.runtime.ConnectNetwork(args[0], args[1])
+		return out, classifyError(err, "container", args[1])
+	default:
+		return "", fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+// classifyError reclassifies err, as returned by a ContainerBackend call
+// against the real Docker Engine API, into one of errdefs's typed kinds.
+// kind ("container", "image", "network") and ref name which concrete
+// not-found error to return; both are ignored for errors that aren't
+// dockererrdefs.IsNotFound.
+func classifyError(err error, kind, ref string) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case dockererrdefs.IsNotFound(err):
+		switch kind {
+		case "container":
+			return errdefs.ErrContainerNotFound{Name: ref, Cause: err}
+		case "image":
+			return errdefs.ErrImageNotFound{Ref: ref, Cause: err}
+		case "network":
+			return errdefs.ErrNetworkNotFound{Name: ref, Cause: err}
+		}
+		return errdefs.ErrDaemonFault{Cause: err}
+	case dockererrdefs.IsUnauthorized(err):
+		return errdefs.ErrNotAuthorized{Cause: err}
+	case dockererrdefs.IsForbidden(err):
+		return errdefs.ErrAccessDenied{Cause: err}
+	case dockererrdefs.IsConflict(err):
+		return errdefs.ErrNameConflict{Message: err.Error(), Cause: err}
+	case dockererrdefs.IsUnavailable(err):
+		return errdefs.ErrDaemonUnreachable{Cause: err}
+	default:
+		return errdefs.ErrDaemonFault{Cause: err}
+	}
+}
+
+// SuggestNextCommand implements CommandService by delegating to runtime,
+// the same heuristics AgentService uses regardless of which backend is
+// wired in.
+func (s *Service) SuggestNextCommand(previousCommand string) string {
+	return s.runtime.SuggestNextCommand(previousCommand)
+}
@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+
+	"cloud-assist/internal/mock"
+)
+
+// EventStream implements mock.EventSource against a real Docker daemon's
+// /events endpoint, the live counterpart to mock.MockEventSource's
+// scripted replay.
+type EventStream struct {
+	cli *client.Client
+}
+
+var _ mock.EventSource = EventStream{}
+
+// NewEventStream connects to the Docker daemon described by the standard
+// DOCKER_HOST / DOCKER_* environment variables.
+func NewEventStream() (EventStream, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return EventStream{}, fmt.Errorf("failed to connect to docker daemon: %w", err)
+	}
+	return EventStream{cli: cli}, nil
+}
+
+// Events implements mock.EventSource, translating the Engine API's
+// events.Message into the runtime-neutral mock.Event shape. The returned
+// channel closes once ctx is done or the daemon connection errors.
+func (e EventStream) Events(ctx context.Context) (<-chan mock.Event, error) {
+	messages, errs := e.cli.Events(ctx, types.EventsOptions{})
+
+	events := make(chan mock.Event)
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				actor := msg.Actor.Attributes["name"]
+				if actor == "" {
+					actor = msg.Actor.ID
+				}
+				select {
+				case events <- mock.Event{
+					Type:   string(msg.Type),
+					Action: msg.Action,
+					Actor:  actor,
+					Time:   time.Unix(msg.Time, 0),
+				}:
+				case <-ctx.Done():
+					return
+				}
+			case <-errs:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
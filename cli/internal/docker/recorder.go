@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"cloud-assist/internal/mock"
+)
+
+// Recorder wraps a CommandService (typically a *Service talking to a
+// real daemon) and captures each ExecuteCommand call's
+// (command, response, latency, success) tuple into a mock.Cassette,
+// instead of requiring the fixtures in mock.NewDockerCommandService to
+// be kept in sync with real output by hand.
+type Recorder struct {
+	inner CommandService
+
+	mu       sync.Mutex
+	cassette mock.Cassette
+}
+
+var _ CommandService = (*Recorder)(nil)
+
+// NewRecorder wraps inner, recording every command it executes.
+func NewRecorder(inner CommandService) *Recorder {
+	return &Recorder{inner: inner, cassette: mock.Cassette{Version: 1}}
+}
+
+// ExecuteCommand runs command against the wrapped service and records
+// the resulting tuple before returning its result unchanged.
+func (r *Recorder) ExecuteCommand(command string) (string, error) {
+	start := time.Now()
+	response, err := r.inner.ExecuteCommand(command)
+	entry := mock.CassetteEntry{
+		Command:  command,
+		Response: response,
+		Latency:  time.Since(start),
+		Success:  err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.cassette.Entries = append(r.cassette.Entries, entry)
+	r.mu.Unlock()
+
+	return response, err
+}
+
+// SuggestNextCommand implements CommandService by delegating to inner;
+// suggestions aren't recorded since they don't touch the daemon.
+func (r *Recorder) SuggestNextCommand(previousCommand string) string {
+	return r.inner.SuggestNextCommand(previousCommand)
+}
+
+// Save writes every tuple recorded so far to path as indented JSON.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette %q: %w", path, err)
+	}
+	return nil
+}
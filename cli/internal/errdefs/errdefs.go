@@ -0,0 +1,77 @@
+// Package errdefs defines typed error kinds for command execution, so
+// callers can distinguish "no such container" from "daemon unreachable"
+// from "permission denied" instead of string-matching Error() text - the
+// same transition moby made from string-checked errors to strongly typed
+// error interfaces (github.com/docker/docker/errdefs).
+package errdefs
+
+import "errors"
+
+// ErrNotFound is implemented by errors for an entity (container, image,
+// network) that doesn't exist.
+type ErrNotFound interface {
+	NotFound()
+}
+
+// ErrConflict is implemented by errors where the requested operation
+// can't be carried out because of the resource's current state (e.g.
+// starting a container whose name is already in use).
+type ErrConflict interface {
+	Conflict()
+}
+
+// ErrUnauthorized is implemented by errors where the daemon rejected the
+// request for lacking valid credentials.
+type ErrUnauthorized interface {
+	Unauthorized()
+}
+
+// ErrForbidden is implemented by errors where the request was
+// authenticated but not permitted.
+type ErrForbidden interface {
+	Forbidden()
+}
+
+// ErrUnavailable is implemented by errors where the daemon itself
+// couldn't be reached, as opposed to the daemon reaching and then
+// rejecting the request.
+type ErrUnavailable interface {
+	Unavailable()
+}
+
+// ErrSystem is implemented by errors for failures internal to the
+// daemon that don't fall into any of the kinds above - the catch-all
+// moby's errdefs calls "system".
+type ErrSystem interface {
+	System()
+}
+
+// IsNotFound reports whether err, or anything it wraps, is an ErrNotFound.
+func IsNotFound(err error) bool { return matches[ErrNotFound](err) }
+
+// IsConflict reports whether err, or anything it wraps, is an ErrConflict.
+func IsConflict(err error) bool { return matches[ErrConflict](err) }
+
+// IsUnauthorized reports whether err, or anything it wraps, is an ErrUnauthorized.
+func IsUnauthorized(err error) bool { return matches[ErrUnauthorized](err) }
+
+// IsForbidden reports whether err, or anything it wraps, is an ErrForbidden.
+func IsForbidden(err error) bool { return matches[ErrForbidden](err) }
+
+// IsUnavailable reports whether err, or anything it wraps, is an ErrUnavailable.
+func IsUnavailable(err error) bool { return matches[ErrUnavailable](err) }
+
+// IsSystem reports whether err, or anything it wraps, is an ErrSystem.
+func IsSystem(err error) bool { return matches[ErrSystem](err) }
+
+// matches walks err's Unwrap chain looking for a T, the way errors.As
+// does for a concrete type, generic over the marker interfaces above.
+func matches[T any](err error) bool {
+	for err != nil {
+		if _, ok := err.(T); ok {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
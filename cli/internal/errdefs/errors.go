@@ -0,0 +1,88 @@
+package errdefs
+
+import "fmt"
+
+// ErrContainerNotFound reports that no container named Name exists.
+type ErrContainerNotFound struct {
+	Name  string
+	Cause error
+}
+
+func (e ErrContainerNotFound) Error() string { return fmt.Sprintf("no such container: %s", e.Name) }
+func (e ErrContainerNotFound) Unwrap() error { return e.Cause }
+func (e ErrContainerNotFound) NotFound()     {}
+
+// ErrImageNotFound reports that no local image matches Ref.
+type ErrImageNotFound struct {
+	Ref   string
+	Cause error
+}
+
+func (e ErrImageNotFound) Error() string { return fmt.Sprintf("no such image: %s", e.Ref) }
+func (e ErrImageNotFound) Unwrap() error { return e.Cause }
+func (e ErrImageNotFound) NotFound()     {}
+
+// ErrNetworkNotFound reports that no network named Name exists.
+type ErrNetworkNotFound struct {
+	Name  string
+	Cause error
+}
+
+func (e ErrNetworkNotFound) Error() string { return fmt.Sprintf("no such network: %s", e.Name) }
+func (e ErrNetworkNotFound) Unwrap() error { return e.Cause }
+func (e ErrNetworkNotFound) NotFound()     {}
+
+// ErrDaemonUnreachable reports that the Docker daemon itself couldn't be
+// contacted (socket missing, DOCKER_HOST unreachable, ...), as opposed to
+// the daemon reaching and then rejecting the request.
+type ErrDaemonUnreachable struct {
+	Cause error
+}
+
+func (e ErrDaemonUnreachable) Error() string {
+	return fmt.Sprintf("docker daemon unreachable: %v", e.Cause)
+}
+func (e ErrDaemonUnreachable) Unwrap() error { return e.Cause }
+func (e ErrDaemonUnreachable) Unavailable()  {}
+
+// ErrNotAuthorized reports that the daemon rejected the request for
+// lacking valid credentials (e.g. a private registry pull).
+type ErrNotAuthorized struct {
+	Cause error
+}
+
+func (e ErrNotAuthorized) Error() string { return fmt.Sprintf("not authorized: %v", e.Cause) }
+func (e ErrNotAuthorized) Unwrap() error { return e.Cause }
+func (e ErrNotAuthorized) Unauthorized() {}
+
+// ErrAccessDenied reports that the daemon authenticated the request but
+// refused to carry it out.
+type ErrAccessDenied struct {
+	Cause error
+}
+
+func (e ErrAccessDenied) Error() string { return fmt.Sprintf("access denied: %v", e.Cause) }
+func (e ErrAccessDenied) Unwrap() error { return e.Cause }
+func (e ErrAccessDenied) Forbidden()    {}
+
+// ErrNameConflict reports that the requested operation can't be carried
+// out because of the resource's current state (e.g. a name already in
+// use, or a container that's already running).
+type ErrNameConflict struct {
+	Message string
+	Cause   error
+}
+
+func (e ErrNameConflict) Error() string { return e.Message }
+func (e ErrNameConflict) Unwrap() error { return e.Cause }
+func (e ErrNameConflict) Conflict()     {}
+
+// ErrDaemonFault wraps a failure internal to the daemon that isn't any
+// of the more specific kinds above.
+type ErrDaemonFault struct {
+	Cause error
+}
+
+func (e ErrDaemonFault) Error() string { return fmt.Sprintf("docker error: %v", e.Cause) }
+func (e ErrDaemonFault) Unwrap() error { return e.Cause }
+func (e ErrDaemonFault) System()       {}
@@ -0,0 +1,208 @@
+// Package history persists chat conversations to disk so they can be
+// resumed across TUI sessions.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoredMessage is a single persisted chat message.
+type StoredMessage struct {
+	Role    string    `json:"role"` // "user", "agent", "command", "command_output", "error"
+	Content string    `json:"content"`
+	Time    time.Time `json:"time"`
+}
+
+// Conversation is one persisted chat session.
+type Conversation struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Messages  []StoredMessage `json:"messages"`
+}
+
+// Store is a JSON-file-backed collection of conversations, one file per
+// conversation under the user's config directory.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore creates a store rooted at "<user config dir>/cloud-assist/conversations".
+func NewStore() (*Store, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user config directory: %w", err)
+	}
+
+	dir := filepath.Join(configDir, "cloud-assist", "conversations")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create conversations directory: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// NewConversation creates and persists an empty conversation, deriving its
+// ID from the current time so conversations sort chronologically.
+func (s *Store) NewConversation() (*Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	conv := &Conversation{
+		ID:        now.Format("20060102T150405.000000000"),
+		Title:     "New conversation",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return conv, s.save(conv)
+}
+
+// LoadConversation loads a conversation by ID.
+func (s *Store) LoadConversation(id string) (*Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation %s: %w", id, err)
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+
+	return &conv, nil
+}
+
+// SaveMessage appends a message to the conversation, deriving the title
+// from the first user message if one hasn't been set yet.
+func (s *Store) SaveMessage(convID string, msg StoredMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.loadLocked(convID)
+	if err != nil {
+		return err
+	}
+
+	conv.Messages = append(conv.Messages, msg)
+	conv.UpdatedAt = msg.Time
+
+	if conv.Title == "" || conv.Title == "New conversation" {
+		if msg.Role == "user" {
+			conv.Title = deriveTitle(msg.Content)
+		}
+	}
+
+	return s.save(conv)
+}
+
+// ListConversations returns all conversations, most recently updated first.
+func (s *Store) ListConversations() ([]Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversations directory: %w", err)
+	}
+
+	var convs []Conversation
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		conv, err := s.loadLocked(id)
+		if err != nil {
+			continue
+		}
+		convs = append(convs, *conv)
+	}
+
+	sortConversationsByRecency(convs)
+	return convs, nil
+}
+
+// RenameConversation sets a conversation's title.
+func (s *Store) RenameConversation(id, title string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.loadLocked(id)
+	if err != nil {
+		return err
+	}
+
+	conv.Title = title
+	return s.save(conv)
+}
+
+// DeleteConversation removes a conversation permanently.
+func (s *Store) DeleteConversation(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete conversation %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *Store) loadLocked(id string) (*Conversation, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation %s: %w", id, err)
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+
+	return &conv, nil
+}
+
+func (s *Store) save(conv *Conversation) error {
+	data, err := json.MarshalIndent(conv, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation %s: %w", conv.ID, err)
+	}
+
+	return os.WriteFile(s.path(conv.ID), data, 0600)
+}
+
+func sortConversationsByRecency(convs []Conversation) {
+	for i := 1; i < len(convs); i++ {
+		for j := i; j > 0 && convs[j].UpdatedAt.After(convs[j-1].UpdatedAt); j-- {
+			convs[j], convs[j-1] = convs[j-1], convs[j]
+		}
+	}
+}
+
+// deriveTitle builds a short title from the first user message.
+func deriveTitle(content string) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\n", " "))
+	const maxLen = 48
+	if len(content) <= maxLen {
+		return content
+	}
+	return content[:maxLen] + "…"
+}
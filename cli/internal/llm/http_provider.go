@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HTTPProvider streams chat completions from an OpenAI-compatible
+// "/chat/completions" endpoint using server-sent events.
+type HTTPProvider struct {
+	Endpoint string
+	APIKey   string
+	Model    string
+	Client   *http.Client
+}
+
+// NewHTTPProvider creates a provider for endpoint, authenticating with
+// apiKey (as retrieved via internal/auth). model selects the model name
+// sent in each request.
+func NewHTTPProvider(endpoint, apiKey, model string) *HTTPProvider {
+	return &HTTPProvider{
+		Endpoint: endpoint,
+		APIKey:   apiKey,
+		Model:    model,
+		Client:   http.DefaultClient,
+	}
+}
+
+type chatCompletionRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// Stream opens a streaming chat completion request and returns a channel
+// of reply chunks. The returned channel is closed, and any goroutine
+// reading the response body stopped, once ctx is canceled.
+func (p *HTTPProvider) Stream(ctx context.Context, messages []Message) (<-chan Chunk, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:    p.Model,
+		Messages: messages,
+		Stream:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach llm provider: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("llm provider returned status %d", resp.StatusCode)
+	}
+
+	chunks := make(chan Chunk)
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				return
+			}
+
+			var parsed chatCompletionChunk
+			if err := json.Unmarshal([]byte(data), &parsed); err != nil {
+				continue
+			}
+			if len(parsed.Choices) == 0 {
+				continue
+			}
+
+			select {
+			case chunks <- Chunk{Content: parsed.Choices[0].Delta.Content}:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			select {
+			case chunks <- Chunk{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return chunks, nil
+}
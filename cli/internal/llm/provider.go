@@ -0,0 +1,26 @@
+// Package llm streams chat completions from a configurable LLM provider,
+// so ui.ChatModel can render replies token-by-token instead of waiting for
+// a full response.
+package llm
+
+import "context"
+
+// Message is one turn in a chat completion request.
+type Message struct {
+	Role    string // "user", "assistant", "system"
+	Content string
+}
+
+// Chunk is one piece of a streamed reply. A Chunk with a non-nil Err ends
+// the stream; the channel is closed once the reply is complete or the
+// context is canceled.
+type Chunk struct {
+	Content string
+	Err     error
+}
+
+// Provider streams a chat completion for the given message history.
+// Canceling ctx stops the stream and closes the returned channel.
+type Provider interface {
+	Stream(ctx context.Context, messages []Message) (<-chan Chunk, error)
+}
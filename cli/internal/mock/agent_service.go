@@ -2,10 +2,13 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
 	"time"
+
+	"cloud-assist/internal/errdefs"
 )
 
 // AgentMessageType defines the type of message in the conversation
@@ -22,6 +25,11 @@ const (
 	TypeCommandOutput AgentMessageType = "command_output"
 	// TypeError represents an error message
 	TypeError AgentMessageType = "error"
+	// TypeLogLine represents one line of a streamed log subscription
+	TypeLogLine AgentMessageType = "log_line"
+	// TypeEvent represents one runtime event from a streamed event
+	// subscription (container exited, network created, ...)
+	TypeEvent AgentMessageType = "event"
 )
 
 // AgentMessage represents a message in the conversation
@@ -29,26 +37,51 @@ type AgentMessage struct {
 	Type    AgentMessageType
 	Content string
 	Time    time.Time
+	// Host names the context a command (or its output) ran against.
+	// Empty means the local daemon; "*" means the message aggregates
+	// results from every configured context.
+	Host string
+	// Stream and Container are set on TypeLogLine messages: Stream is
+	// "stdout" or "stderr", Container is the container the line came from.
+	Stream    string
+	Container string
 }
 
+// aggregateHost is the sentinel AgentService.commandHost takes when a
+// command should fan out across every configured context instead of
+// running against a single one.
+const aggregateHost = "*"
+
 // AgentService simulates the Cloud-Assist agent for UI testing
 type AgentService struct {
-	dockerService      *DockerCommandService
+	backend            ContainerBackend
+	runtime            ContainerRuntime
+	hosts              *HostManager
+	commandHost        string
+	waitConfig         WaitConfig
+	report             *SessionReport
 	conversationState  string
 	previousCommand    string
 	welcomeMessages    []string
 	responseTemplates  map[string][]string
-	explanationMethods map[string]func(string) string
+	explanationMethods map[Capability]func(args []string) string
 	contextHistory     []string
 	scenario           string
 	userPreferences    map[string]string
 	scenarioProgress   int
 }
 
-// NewAgentService creates a new mock agent service
-func NewAgentService() *AgentService {
+// NewAgentService creates a new agent service backed by backend. Pass a
+// *DockerCommandService for tests and local UI development, or a
+// *DockerEngineBackend to drive a real Docker daemon. The agent suggests
+// Docker commands by default; use NewAgentServiceWithRuntime to target
+// Podman or Singularity instead.
+func NewAgentService(backend ContainerBackend) *AgentService {
 	agent := &AgentService{
-		dockerService:     NewDockerCommandService(),
+		backend:           backend,
+		runtime:           &DockerRuntime{},
+		waitConfig:        loadDefaultWaitConfig(),
+		report:            NewSessionReport(),
 		conversationState: "initial",
 		welcomeMessages: []string{
 			"Welcome to Cloud-Assist! I'm your AI-powered DevOps assistant. How can I help you today?",
@@ -56,7 +89,7 @@ func NewAgentService() *AgentService {
 			"Hello! I'm your Cloud-Assist AI agent, ready to help with your infrastructure needs. What are you working on?",
 		},
 		responseTemplates:  make(map[string][]string),
-		explanationMethods: make(map[string]func(string) string),
+		explanationMethods: make(map[Capability]func(args []string) string),
 		contextHistory:     []string{},
 		scenario:           "docker",
 		userPreferences:    make(map[string]string),
@@ -110,104 +143,154 @@ func NewAgentService() *AgentService {
 		"potential firewall or security group restrictions",
 	}
 
-	// Add explanation methods for different commands - make these more AI-like and educational
-	agent.explanationMethods["docker ps"] = func(cmd string) string {
-		return "The `docker ps` command lists all running containers on your system. It displays container IDs, " +
-			"the image used, when they were created, their current status, exposed ports, and assigned names. This gives you " +
-			"a quick overview of what's active in your Docker environment.\n\n" +
-			"I recommended this command because it's essential to understand what's currently running before taking further actions."
+	// Add explanation methods for different commands - make these more AI-like
+	// and educational. They're keyed by Capability rather than a literal
+	// "docker ..." string so the same explanations serve Podman or
+	// Singularity once ExplainCommand recovers the capability from
+	// a.runtime.Parse instead of prefix-matching the command text.
+	agent.explanationMethods[CapabilityListContainers] = func(args []string) string {
+		return fmt.Sprintf("The `%s` command lists all running containers on your system. It displays container IDs, "+
+			"the image used, when they were created, their current status, exposed ports, and assigned names. This gives you "+
+			"a quick overview of what's active in your %s environment.\n\n"+
+			"I recommended this command because it's essential to understand what's currently running before taking further actions.", agent.runtime.CommandFor(CapabilityListContainers), agent.runtime.Name())
 	}
 
-	agent.explanationMethods["docker ps -a"] = func(cmd string) string {
-		return "The `docker ps -a` command shows all containers on your system, including those that have stopped or exited. " +
-			"The `-a` flag stands for 'all' and provides a complete view of your container environment.\n\n" +
-			"This command is particularly useful when troubleshooting because it shows containers that may have crashed or " +
-			"exited unexpectedly, along with their exit codes which can help diagnose issues."
+	agent.explanationMethods[CapabilityListAllContainers] = func(args []string) string {
+		return fmt.Sprintf("The `%s` command shows all containers on your system, including those that have stopped or exited. "+
+			"The 'all' flag provides a complete view of your container environment.\n\n"+
+			"This command is particularly useful when troubleshooting because it shows containers that may have crashed or "+
+			"exited unexpectedly, along with their exit codes which can help diagnose issues.", agent.runtime.CommandFor(CapabilityListAllContainers))
 	}
 
-	agent.explanationMethods["docker logs"] = func(cmd string) string {
-		parts := strings.Split(cmd, " ")
-		if len(parts) > 2 {
-			containerName := parts[2]
-			return fmt.Sprintf("The `docker logs %s` command fetches and displays the logs generated by the '%s' container. "+
+	agent.explanationMethods[CapabilityLogs] = func(args []string) string {
+		if len(args) > 0 {
+			containerName := args[0]
+			return fmt.Sprintf("The `%s` command fetches and displays the logs generated by the '%s' container. "+
 				"This includes both stdout and stderr output streams.\n\n"+
 				"I recommended checking the logs because they often contain valuable diagnostic information that can help "+
-				"identify why a container is behaving unexpectedly or what errors it might be encountering.", containerName, containerName)
+				"identify why a container is behaving unexpectedly or what errors it might be encountering.", agent.runtime.CommandFor(CapabilityLogs, containerName), containerName)
 		}
-		return "The `docker logs` command displays the logs from a specified container, showing its stdout and stderr output streams. " +
-			"This is crucial for debugging container issues and understanding application behavior."
+		return fmt.Sprintf("The `%s logs` command displays the logs from a specified container, showing its stdout and stderr output streams. "+
+			"This is crucial for debugging container issues and understanding application behavior.", agent.runtime.Name())
 	}
 
-	agent.explanationMethods["docker start"] = func(cmd string) string {
-		parts := strings.Split(cmd, " ")
-		if len(parts) > 2 {
-			containerName := parts[2]
-			return fmt.Sprintf("The `docker start %s` command starts the stopped container named '%s'. "+
+	agent.explanationMethods[CapabilityStart] = func(args []string) string {
+		if len(args) > 0 {
+			containerName := args[0]
+			return fmt.Sprintf("The `%s` command starts the stopped container named '%s'. "+
 				"This resumes the container in its previous state without creating a new container instance.\n\n"+
 				"I recommended starting this container because it appears to be stopped but needed for your application stack "+
-				"to function properly. Starting it will restore the service without losing any container-specific data.", containerName, containerName)
+				"to function properly. Starting it will restore the service without losing any container-specific data.", agent.runtime.CommandFor(CapabilityStart, containerName), containerName)
 		}
-		return "The `docker start` command resumes a stopped container while preserving its state, volumes, and configuration. " +
-			"This is more efficient than creating a new container when you simply need to resume operations."
+		return fmt.Sprintf("The `%s start` command resumes a stopped container while preserving its state, volumes, and configuration. "+
+			"This is more efficient than creating a new container when you simply need to resume operations.", agent.runtime.Name())
 	}
 
-	agent.explanationMethods["docker restart"] = func(cmd string) string {
-		parts := strings.Split(cmd, " ")
-		if len(parts) > 2 {
-			containerName := parts[2]
-			return fmt.Sprintf("The `docker restart %s` command stops and then starts the '%s' container in one operation. "+
+	agent.explanationMethods[CapabilityRestart] = func(args []string) string {
+		if len(args) > 0 {
+			containerName := args[0]
+			return fmt.Sprintf("The `%s` command stops and then starts the '%s' container in one operation. "+
 				"This can resolve many common issues by refreshing the container's processes and connections.\n\n"+
 				"I recommended restarting this container because the logs indicated connection issues that are often fixed "+
-				"by a clean restart, which clears temporary state and re-establishes connections.", containerName, containerName)
+				"by a clean restart, which clears temporary state and re-establishes connections.", agent.runtime.CommandFor(CapabilityRestart, containerName), containerName)
 		}
-		return "The `docker restart` command stops and then starts a container in one operation. It's an efficient way to " +
-			"refresh a container's state when it's encountering transient issues without having to manually stop and start it separately."
-	}
-
-	agent.explanationMethods["docker network"] = func(cmd string) string {
-		if strings.Contains(cmd, "inspect") {
-			parts := strings.Split(cmd, " ")
-			if len(parts) > 3 {
-				networkName := parts[3]
-				return fmt.Sprintf("The `docker network inspect %s` command provides detailed information about the '%s' network. "+
-					"It shows the network's configuration, connected containers, IP addresses, and gateway information.\n\n"+
-					"I suggested inspecting this network because understanding the current network topology is essential for "+
-					"diagnosing communication issues between containers.", networkName, networkName)
-			}
-			return "This command provides detailed information about a Docker network's configuration and which containers are attached to it."
-		} else if strings.Contains(cmd, "ls") {
-			return "The `docker network ls` command lists all networks on your Docker system. " +
-				"I recommended this to get an overview of the available networks, which is essential for understanding " +
-				"how your containers can communicate with each other. Container networking issues are a common source of problems in " +
-				"multi-container applications."
-		} else if strings.Contains(cmd, "connect") {
-			parts := strings.Split(cmd, " ")
-			if len(parts) >= 4 {
-				networkName := parts[2]
-				containerName := parts[3]
-				return fmt.Sprintf("The `docker network connect %s %s` command connects the '%s' container to the '%s' network. "+
-					"This allows the container to communicate with other containers on that network.\n\n"+
-					"I suggested connecting this container to the network because the error logs indicated connection issues "+
-					"that are likely due to network isolation.", networkName, containerName, containerName, networkName)
-			}
-			return "This command connects a container to a network, enabling it to communicate with other containers on that network."
+		return fmt.Sprintf("The `%s restart` command stops and then starts a container in one operation. It's an efficient way to "+
+			"refresh a container's state when it's encountering transient issues without having to manually stop and start it separately.", agent.runtime.Name())
+	}
+
+	agent.explanationMethods[CapabilityNetworkInspect] = func(args []string) string {
+		if len(args) > 0 {
+			networkName := args[0]
+			return fmt.Sprintf("The `%s` command provides detailed information about the '%s' network. "+
+				"It shows the network's configuration, connected containers, IP addresses, and gateway information.\n\n"+
+				"I suggested inspecting this network because understanding the current network topology is essential for "+
+				"diagnosing communication issues between containers.", agent.runtime.CommandFor(CapabilityNetworkInspect, networkName), networkName)
+		}
+		return fmt.Sprintf("This command provides detailed information about a %s network's configuration and which containers are attached to it.", agent.runtime.Name())
+	}
+
+	agent.explanationMethods[CapabilityNetworkList] = func(args []string) string {
+		return fmt.Sprintf("The `%s` command lists all networks on your %s system. "+
+			"I recommended this to get an overview of the available networks, which is essential for understanding "+
+			"how your containers can communicate with each other. Container networking issues are a common source of problems in "+
+			"multi-container applications.", agent.runtime.CommandFor(CapabilityNetworkList), agent.runtime.Name())
+	}
+
+	agent.explanationMethods[CapabilityNetworkConnect] = func(args []string) string {
+		if len(args) >= 2 {
+			networkName, containerName := args[0], args[1]
+			return fmt.Sprintf("The `%s` command connects the '%s' container to the '%s' network. "+
+				"This allows the container to communicate with other containers on that network.\n\n"+
+				"I suggested connecting this container to the network because the error logs indicated connection issues "+
+				"that are likely due to network isolation.", agent.runtime.CommandFor(CapabilityNetworkConnect, networkName, containerName), containerName, networkName)
 		}
-		return "Docker network commands manage container networking, allowing you to create, inspect, and modify networks " +
-			"to control how containers communicate with each other and the outside world."
+		return "This command connects a container to a network, enabling it to communicate with other containers on that network."
 	}
 
-	// Add fallback explanation method with more AI-like language
-	agent.explanationMethods["default"] = func(cmd string) string {
-		return fmt.Sprintf("The `%s` command is a Docker operation that interacts with your container environment. Based on your current context, I recommended it because it addresses the specific issue or task you're working on. Would you like me to provide a more detailed explanation of what this command does and why I suggested it?", cmd)
+	agent.explanationMethods[CapabilityImages] = func(args []string) string {
+		return fmt.Sprintf("The `%s` command lists the container images available on your system, including their repository, "+
+			"tag, and size. I recommended it to confirm an image is present before you try to run a container from it.", agent.runtime.CommandFor(CapabilityImages))
 	}
 
 	return agent
 }
 
+// NewAgentServiceWithHosts creates an agent service that can also address
+// remote Docker contexts loaded by hosts, routing host-qualified commands
+// ("check logs on prod-eu") through the backend bound to that host instead
+// of backend.
+func NewAgentServiceWithHosts(backend ContainerBackend, hosts *HostManager) *AgentService {
+	agent := NewAgentService(backend)
+	agent.hosts = hosts
+	return agent
+}
+
+// NewAgentServiceWithRuntime creates an agent service that suggests
+// commands for runtime (Podman, Singularity, ...) instead of Docker.
+// runtime is also used as the backend, since a ContainerRuntime
+// satisfies ContainerBackend, so AgentService dispatches suggested
+// commands against the same tool it names them after.
+func NewAgentServiceWithRuntime(runtime ContainerRuntime) *AgentService {
+	agent := NewAgentService(runtime)
+	agent.runtime = runtime
+	return agent
+}
+
+// extractHostQualifier strips a trailing "on <host>" clause naming a known
+// context from message, returning the cleaned message and the host name
+// ("" if none was found). "on all hosts"/"on every host" resolves to the
+// aggregateHost sentinel instead of a single context name.
+func (a *AgentService) extractHostQualifier(message string) (string, string) {
+	if a.hosts == nil {
+		return message, ""
+	}
+
+	lower := strings.ToLower(message)
+	idx := strings.LastIndex(lower, " on ")
+	if idx == -1 {
+		return message, ""
+	}
+
+	qualifier := strings.TrimSpace(message[idx+len(" on "):])
+	cleaned := strings.TrimSpace(message[:idx])
+
+	lowerQualifier := strings.ToLower(qualifier)
+	if lowerQualifier == "all hosts" || lowerQualifier == "every host" {
+		return cleaned, aggregateHost
+	}
+	if a.hosts.HasContext(qualifier) {
+		return cleaned, qualifier
+	}
+
+	return message, ""
+}
+
 // ProcessUserMessage processes a user message and returns agent responses
 func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 	var responses []AgentMessage
 
+	message, host := a.extractHostQualifier(message)
+
 	// Track user input in context history
 	if message != "help" && message != "e" && message != "y" && message != "n" && message != "q" {
 		a.contextHistory = append(a.contextHistory, "User: "+message)
@@ -250,7 +333,7 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 		})
 
 		// Suggest the first command
-		suggestedCmd := "docker ps"
+		suggestedCmd := a.runtime.CommandFor(CapabilityListContainers)
 		responses = append(responses, AgentMessage{
 			Type:    TypeCommand,
 			Content: suggestedCmd,
@@ -258,6 +341,7 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 		})
 
 		a.previousCommand = suggestedCmd
+		a.commandHost = host
 		a.conversationState = "awaiting_approval"
 		a.contextHistory = append(a.contextHistory, "Agent suggested: "+suggestedCmd)
 		return responses
@@ -296,52 +380,57 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 
 	switch detectedIntent {
 	case "list_containers":
-		agentResponse = "I'll help you check your containers. Let me get a list of all containers including stopped ones for a complete picture:"
-		suggestedCmd = "docker ps -a"
+		if host == aggregateHost {
+			agentResponse = "I'll check running containers across every configured host and merge the results:"
+			suggestedCmd = a.runtime.CommandFor(CapabilityListContainers)
+		} else {
+			agentResponse = "I'll help you check your containers. Let me get a list of all containers including stopped ones for a complete picture:"
+			suggestedCmd = a.runtime.CommandFor(CapabilityListAllContainers)
+		}
 	case "check_logs":
 		if strings.Contains(lowerMessage, "web") || strings.Contains(lowerMessage, "server") {
 			agentResponse = "Let me check the logs for the web-server container to help diagnose any issues:"
-			suggestedCmd = "docker logs web-server"
+			suggestedCmd = a.runtime.CommandFor(CapabilityLogs, "web-server")
 		} else if strings.Contains(lowerMessage, "app") {
 			agentResponse = "I'll examine the logs for the app container to see why it might be failing:"
-			suggestedCmd = "docker logs app"
+			suggestedCmd = a.runtime.CommandFor(CapabilityLogs, "app")
 		} else {
 			agentResponse = "I'll check the logs for the redis-cache service since that's a common dependency that might be causing issues:"
-			suggestedCmd = "docker logs redis-cache"
+			suggestedCmd = a.runtime.CommandFor(CapabilityLogs, "redis-cache")
 		}
 	case "network_operations":
 		if strings.Contains(lowerMessage, "inspect") || strings.Contains(lowerMessage, "detail") {
 			agentResponse = "Let me inspect the application network to see which containers are connected and their IP configurations:"
-			suggestedCmd = "docker network inspect my-application"
+			suggestedCmd = a.runtime.CommandFor(CapabilityNetworkInspect, "my-application")
 		} else {
 			agentResponse = "I'll list all the networks in your environment so we can see what's available:"
-			suggestedCmd = "docker network ls"
+			suggestedCmd = a.runtime.CommandFor(CapabilityNetworkList)
 		}
 	case "image_operations":
-		agentResponse = "Here are the Docker images currently available on your system:"
-		suggestedCmd = "docker images"
+		agentResponse = fmt.Sprintf("Here are the %s images currently available on your system:", a.runtime.Name())
+		suggestedCmd = a.runtime.CommandFor(CapabilityImages)
 	case "start_container":
 		agentResponse = "I'll start the app container for you. This will bring it online without creating a new container instance:"
-		suggestedCmd = "docker start app"
+		suggestedCmd = a.runtime.CommandFor(CapabilityStart, "app")
 	case "restart_container":
 		if strings.Contains(lowerMessage, "redis") || strings.Contains(lowerMessage, "cache") {
 			agentResponse = "I'll restart the redis-cache container to refresh its connections:"
-			suggestedCmd = "docker restart redis-cache"
+			suggestedCmd = a.runtime.CommandFor(CapabilityRestart, "redis-cache")
 		} else {
 			agentResponse = "I'll restart the web-server container to apply any configuration changes:"
-			suggestedCmd = "docker restart web-server"
+			suggestedCmd = a.runtime.CommandFor(CapabilityRestart, "web-server")
 		}
 	case "stop_container":
 		agentResponse = "I'll stop the web-server container safely, allowing it to shutdown gracefully:"
-		suggestedCmd = "docker stop web-server"
+		suggestedCmd = a.runtime.CommandFor(CapabilityStop, "web-server")
 	case "troubleshooting":
 		// For troubleshooting, simulate a more thoughtful analysis
 		agentResponse = "Based on the information you've provided, there might be an issue with container networking or service dependencies. Let me first check which containers are running and their status:"
-		suggestedCmd = "docker ps -a"
+		suggestedCmd = a.runtime.CommandFor(CapabilityListAllContainers)
 	default:
 		// Default behavior with more AI-like reasoning
-		agentResponse = "I understand you're working with Docker containers. To best assist you, let me first understand your current environment by checking your running containers:"
-		suggestedCmd = "docker ps"
+		agentResponse = fmt.Sprintf("I understand you're working with %s containers. To best assist you, let me first understand your current environment by checking your running containers:", a.runtime.Name())
+		suggestedCmd = a.runtime.CommandFor(CapabilityListContainers)
 	}
 
 	// Add agent response with AI-like behavior
@@ -349,6 +438,7 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 		Type:    TypeAgent,
 		Content: agentResponse,
 		Time:    time.Now(),
+		Host:    host,
 	})
 
 	// Add command suggestion
@@ -356,10 +446,12 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 		Type:    TypeCommand,
 		Content: suggestedCmd,
 		Time:    time.Now().Add(1 * time.Second),
+		Host:    host,
 	})
 
 	// Update state
 	a.previousCommand = suggestedCmd
+	a.commandHost = host
 	a.conversationState = "awaiting_approval"
 	a.contextHistory = append(a.contextHistory, "Agent analyzed: "+detectedIntent)
 	a.contextHistory = append(a.contextHistory, "Agent suggested: "+suggestedCmd)
@@ -371,8 +463,40 @@ func (a *AgentService) ProcessUserMessage(message string) []AgentMessage {
 func (a *AgentService) ExecuteSuggestedCommand() []AgentMessage {
 	var responses []AgentMessage
 
-	// Execute the command
-	output, err := a.dockerService.ExecuteCommand(a.previousCommand)
+	// Aggregate mode fans the command out across every configured context
+	// instead of dispatching to a single backend.
+	if a.commandHost == aggregateHost && a.hosts != nil && a.previousCommand == a.runtime.CommandFor(CapabilityListContainers) {
+		output := a.hosts.AggregatePS(false)
+		responses = append(responses, AgentMessage{
+			Type:    TypeCommandOutput,
+			Content: output,
+			Time:    time.Now(),
+			Host:    aggregateHost,
+		})
+
+		nextCommand := a.backend.SuggestNextCommand(a.previousCommand)
+		responses = append(responses, AgentMessage{
+			Type:    TypeCommand,
+			Content: nextCommand,
+			Time:    time.Now().Add(1 * time.Second),
+		})
+
+		a.previousCommand = nextCommand
+		a.commandHost = ""
+		a.conversationState = "awaiting_approval"
+		return responses
+	}
+
+	backend := a.backend
+	if a.commandHost != "" && a.hosts != nil {
+		hostBackend, err := a.hosts.Backend(a.commandHost)
+		if err == nil {
+			backend = hostBackend
+		}
+	}
+
+	// Dispatch the command to a typed backend call
+	output, err := a.dispatchCommand(backend, a.previousCommand)
 
 	// Command output
 	if err == nil {
@@ -380,19 +504,22 @@ func (a *AgentService) ExecuteSuggestedCommand() []AgentMessage {
 			Type:    TypeCommandOutput,
 			Content: output,
 			Time:    time.Now(),
+			Host:    a.commandHost,
 		})
 
-		// Update state based on the command
+		// Update state based on the command's capability
 		newState := "after_command"
-		if strings.HasPrefix(a.previousCommand, "docker ps") {
-			newState = "after_docker_ps"
-		} else if strings.HasPrefix(a.previousCommand, "docker logs") {
-			newState = "after_logs"
-		} else if strings.HasPrefix(a.previousCommand, "docker network") {
-			newState = "after_network"
-		} else if strings.HasPrefix(a.previousCommand, "docker start") ||
-			strings.HasPrefix(a.previousCommand, "docker restart") {
-			newState = "after_start"
+		if capability, _, ok := a.runtime.Parse(a.previousCommand); ok {
+			switch capability {
+			case CapabilityListContainers, CapabilityListAllContainers:
+				newState = "after_docker_ps"
+			case CapabilityLogs:
+				newState = "after_logs"
+			case CapabilityNetworkList, CapabilityNetworkInspect, CapabilityNetworkConnect:
+				newState = "after_network"
+			case CapabilityStart, CapabilityRestart:
+				newState = "after_start"
+			}
 		}
 
 		a.conversationState = newState
@@ -415,35 +542,77 @@ func (a *AgentService) ExecuteSuggestedCommand() []AgentMessage {
 				Type:    TypeAgent,
 				Content: responseContent,
 				Time:    time.Now().Add(1 * time.Second),
+				Host:    a.commandHost,
 			})
 		}
 
+		entry := CommandEntry{Command: a.previousCommand, Host: a.commandHost, Approved: true, Output: output, Time: time.Now()}
+
+		// Verify the container actually came up healthy after a command
+		// that changes its running state, instead of just trusting the
+		// Docker CLI's exit code.
+		if verifyContainer := a.verifyTargetOf(a.previousCommand); verifyContainer != "" {
+			verifyMessages, diagnosticCmd, waitResult := a.verifyContainerHealth(backend, verifyContainer)
+			entry.WaitResult = waitResult
+			responses = append(responses, verifyMessages...)
+			if diagnosticCmd != "" {
+				a.report.Record(entry)
+				responses = append(responses, AgentMessage{
+					Type:    TypeCommand,
+					Content: diagnosticCmd,
+					Time:    time.Now().Add(3 * time.Second),
+					Host:    a.commandHost,
+				})
+				a.previousCommand = diagnosticCmd
+				a.conversationState = "awaiting_approval"
+				return responses
+			}
+		}
+
+		a.report.Record(entry)
+
 		// Suggest next command
-		nextCommand := a.dockerService.SuggestNextCommand(a.previousCommand)
+		nextCommand := backend.SuggestNextCommand(a.previousCommand)
 		responses = append(responses, AgentMessage{
 			Type:    TypeCommand,
 			Content: nextCommand,
 			Time:    time.Now().Add(2 * time.Second),
+			Host:    a.commandHost,
 		})
 
 		a.previousCommand = nextCommand
 		a.conversationState = "awaiting_approval"
 	} else {
+		a.report.Record(CommandEntry{Command: a.previousCommand, Host: a.commandHost, Approved: true, Err: err.Error(), Time: time.Now()})
+
 		// Error case
 		responses = append(responses, AgentMessage{
 			Type:    TypeError,
 			Content: err.Error(),
 			Time:    time.Now(),
+			Host:    a.commandHost,
 		})
 
+		// The follow-up message and fallback command depend on what kind
+		// of error this was: listing containers won't help if the
+		// runtime itself is unreachable, so offer a retry of the same
+		// command instead of the default "list everything" suggestion.
+		agentMessage := "There was an error executing that command. Would you like to try something else?"
+		fallbackCmd := a.runtime.CommandFor(CapabilityListAllContainers)
+		switch {
+		case errdefs.IsUnavailable(err):
+			agentMessage = "The container runtime couldn't be reached. Want to retry the same command?"
+			fallbackCmd = a.previousCommand
+		case errdefs.IsNotFound(err):
+			agentMessage = "That doesn't look like it exists. Here's what's actually running:"
+		}
+
 		responses = append(responses, AgentMessage{
 			Type:    TypeAgent,
-			Content: "There was an error executing that command. Would you like to try something else?",
+			Content: agentMessage,
 			Time:    time.Now().Add(1 * time.Second),
 		})
 
-		// Suggest a fallback command
-		fallbackCmd := "docker ps -a"
 		responses = append(responses, AgentMessage{
 			Type:    TypeCommand,
 			Content: fallbackCmd,
@@ -451,21 +620,122 @@ func (a *AgentService) ExecuteSuggestedCommand() []AgentMessage {
 		})
 
 		a.previousCommand = fallbackCmd
+		a.commandHost = ""
 		a.conversationState = "awaiting_approval"
 	}
 
 	return responses
 }
 
-// ExplainCommand provides an explanation for a Docker command
+// dispatchCommand translates one of the canned commands AgentService
+// suggests into the matching typed call against backend, by recovering
+// its Capability through a.runtime.Parse so execution never depends on
+// string-parsing a runtime-specific shell command.
+func (a *AgentService) dispatchCommand(backend ContainerBackend, command string) (string, error) {
+	capability, args, ok := a.runtime.Parse(command)
+	if !ok {
+		return "", fmt.Errorf("unknown command: %s", command)
+	}
+
+	switch capability {
+	case CapabilityListContainers:
+		return backend.ListContainers(false)
+	case CapabilityListAllContainers:
+		return backend.ListContainers(true)
+	case CapabilityImages:
+		return backend.Images()
+	case CapabilityNetworkList:
+		return backend.ListNetworks()
+	case CapabilityLogs:
+		return backend.Logs(args[0])
+	case CapabilityStart:
+		return backend.Start(args[0])
+	case CapabilityRestart:
+		return backend.Restart(args[0])
+	case CapabilityStop:
+		return backend.Stop(args[0])
+	case CapabilityNetworkInspect:
+		return backend.InspectNetwork(args[0])
+	case CapabilityNetworkConnect:
+		return backend.ConnectNetwork(args[0], args[1])
+	default:
+		return "", fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+// verifyTargetOf returns the container name to run a post-execution
+// WaitStrategy against after command, or "" if command doesn't change a
+// container's running state.
+func (a *AgentService) verifyTargetOf(command string) string {
+	capability, args, ok := a.runtime.Parse(command)
+	if !ok {
+		return ""
+	}
+
+	switch capability {
+	case CapabilityStart, CapabilityRestart:
+		return args[0]
+	case CapabilityNetworkConnect:
+		return args[1]
+	default:
+		return ""
+	}
+}
+
+// verifyContainerHealth runs the WaitStrategy configured (or inferred)
+// for container against backend and turns the result into conversation
+// messages: a TypeAgent summary on success, or a TypeError explanation
+// plus a diagnostic follow-up command to suggest on failure/timeout.
+func (a *AgentService) verifyContainerHealth(backend ContainerBackend, container string) (messages []AgentMessage, diagnosticCmd string, waitResult *WaitResult) {
+	strategy, timeout, ok := a.waitConfig.StrategyFor(container)
+	if !ok {
+		return nil, "", nil
+	}
+
+	result := Wait(context.Background(), backend, container, strategy, timeout)
+	elapsed := result.Elapsed.Round(100 * time.Millisecond)
+
+	if result.Success {
+		messages = append(messages, AgentMessage{
+			Type:    TypeAgent,
+			Content: fmt.Sprintf("Verified %s: %s (after %s).", container, result.Detail, elapsed),
+			Time:    time.Now().Add(2 * time.Second),
+			Host:    a.commandHost,
+		})
+		return messages, "", &result
+	}
+
+	outcome := "failed"
+	if result.TimedOut {
+		outcome = "timed out"
+	}
+	messages = append(messages, AgentMessage{
+		Type:    TypeError,
+		Content: fmt.Sprintf("Verification %s for %s after %s: %s", outcome, container, elapsed, result.Detail),
+		Time:    time.Now().Add(2 * time.Second),
+		Host:    a.commandHost,
+	})
+
+	return messages, a.runtime.CommandFor(CapabilityLogs, container), &result
+}
+
+// Report returns a snapshot of the SessionReport accumulated so far this
+// conversation, for flushing through a Notifier at session end or on a
+// timer/SIGINT - a copy, since the SIGINT handler and the normal return
+// path can both call Report while command processing is still appending
+// to the live report on another goroutine.
+func (a *AgentService) Report() *SessionReport {
+	return a.report.Snapshot()
+}
+
+// ExplainCommand provides an explanation for a suggested command, in
+// whatever runtime's CLI syntax it was rendered in.
 func (a *AgentService) ExplainCommand(command string) string {
-	// Find the most specific explanation method
-	for cmdPrefix, explainFunc := range a.explanationMethods {
-		if strings.HasPrefix(command, cmdPrefix) {
-			return explainFunc(command)
+	if capability, args, ok := a.runtime.Parse(command); ok {
+		if explainFunc, ok := a.explanationMethods[capability]; ok {
+			return explainFunc(args)
 		}
 	}
 
-	// Use default explanation if no specific one is found
-	return a.explanationMethods["default"](command)
+	return fmt.Sprintf("The `%s` command is a %s operation that interacts with your container environment. Based on your current context, I recommended it because it addresses the specific issue or task you're working on. Would you like me to provide a more detailed explanation of what this command does and why I suggested it?", command, a.runtime.Name())
 }
@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAgentServiceDispatchesThroughMockBackend exercises the seam
+// NewAgentService's ContainerBackend parameter exists for: the agent
+// suggests a command using the runtime-neutral Capability abstraction,
+// then ExecuteSuggestedCommand dispatches it through dispatchCommand to
+// typed backend calls, all without AgentService ever naming Docker's CLI
+// syntax directly. A production run would pass a real Docker-SDK-backed
+// backend here; tests keep the *DockerCommandService fixture instead.
+func TestAgentServiceDispatchesThroughMockBackend(t *testing.T) {
+	agent := NewAgentService(NewDockerCommandService())
+
+	initial := agent.ProcessUserMessage("hello")
+
+	var suggested string
+	for _, msg := range initial {
+		if msg.Type == TypeCommand {
+			suggested = msg.Content
+		}
+	}
+	if want := "docker ps"; suggested != want {
+		t.Fatalf("initial suggested command = %q, want %q", suggested, want)
+	}
+
+	approved := agent.ProcessUserMessage("y")
+
+	var output string
+	for _, msg := range approved {
+		if msg.Type == TypeCommandOutput {
+			output = msg.Content
+		}
+	}
+	if output == "" {
+		t.Fatalf("ExecuteSuggestedCommand produced no TypeCommandOutput message")
+	}
+	if want := "web-server"; !strings.Contains(output, want) {
+		t.Fatalf("command output = %q, want it to contain the mock fixture's %q", output, want)
+	}
+}
@@ -0,0 +1,31 @@
+// Package mock provides mock implementations for testing UI components
+package mock
+
+// ContainerBackend abstracts the container runtime operations AgentService
+// needs to carry out a suggested command. DockerCommandService implements
+// it against canned fixtures for tests and local UI development; a real
+// implementation can talk to an actual Docker daemon without AgentService
+// having to change.
+type ContainerBackend interface {
+	// ListContainers lists containers. all also includes stopped containers.
+	ListContainers(all bool) (string, error)
+	// Logs returns the logs for the named container.
+	Logs(container string) (string, error)
+	// ListNetworks lists networks.
+	ListNetworks() (string, error)
+	// InspectNetwork returns detailed configuration for the named network.
+	InspectNetwork(network string) (string, error)
+	// Start starts the named container.
+	Start(container string) (string, error)
+	// Restart stops and starts the named container.
+	Restart(container string) (string, error)
+	// Stop stops the named container.
+	Stop(container string) (string, error)
+	// ConnectNetwork attaches container to network.
+	ConnectNetwork(network, container string) (string, error)
+	// Images lists locally available images.
+	Images() (string, error)
+	// SuggestNextCommand suggests the next command to run, given the
+	// previously executed one.
+	SuggestNextCommand(previousCommand string) string
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// cassetteVersion is bumped whenever CassetteEntry's on-disk shape
+// changes, so LoadCassette can reject a cassette recorded against an
+// incompatible version instead of silently misinterpreting it.
+const cassetteVersion = 1
+
+// CassetteEntry is one recorded (command, response, latency, success)
+// tuple, the on-disk shape internal/docker.Recorder writes and
+// LoadCassette reads back.
+type CassetteEntry struct {
+	Command  string        `json:"command"`
+	Response string        `json:"response"`
+	Latency  time.Duration `json:"latency"`
+	Success  bool          `json:"success"`
+	Error    string        `json:"error,omitempty"`
+	// IsFallback marks an entry that should seed fallbackResponses
+	// (matched by prefix) instead of commands (matched exactly).
+	// Recorder never sets this - it's for cassettes hand-edited after
+	// recording - so Command is treated as a prefix only when it's true.
+	IsFallback bool `json:"is_fallback,omitempty"`
+}
+
+// Cassette is a versioned collection of recorded command/response
+// tuples, the file format cloud-assist's "record" subcommand writes and
+// DockerCommandService.LoadCassette reads.
+type Cassette struct {
+	Version int             `json:"version"`
+	Entries []CassetteEntry `json:"entries"`
+}
+
+// LoadCassette seeds s.commands and s.fallbackResponses from the
+// recorded tuples at path, the way cloud-assist record
+// (internal/docker.Recorder) wrote them against a real daemon -
+// contributors can regenerate the fixture corpus against their own
+// daemon instead of editing the AddCommand calls in
+// NewDockerCommandService by hand.
+//
+// cassette_test.go loads cassettes through this method; no UI test
+// does.
+func (s *DockerCommandService) LoadCassette(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cassette %q: %w", path, err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return fmt.Errorf("failed to parse cassette %q: %w", path, err)
+	}
+	if cassette.Version != cassetteVersion {
+		return fmt.Errorf("cassette %q has version %d, expected %d", path, cassette.Version, cassetteVersion)
+	}
+
+	for _, entry := range cassette.Entries {
+		if entry.IsFallback {
+			s.AddFallbackResponse(entry.Command, entry.Response)
+			continue
+		}
+		s.AddCommand(entry.Command, entry.Response, entry.Latency, entry.Success)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCassette marshals cassette to a temp file and returns its path.
+func writeCassette(t *testing.T, cassette Cassette) string {
+	t.Helper()
+	data, err := json.Marshal(cassette)
+	if err != nil {
+		t.Fatalf("failed to marshal cassette: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write cassette: %v", err)
+	}
+	return path
+}
+
+func TestLoadCassetteSeedsCommandsAndFallbacks(t *testing.T) {
+	path := writeCassette(t, Cassette{
+		Version: cassetteVersion,
+		Entries: []CassetteEntry{
+			{Command: "docker ps", Response: "CONTAINER ID\nweb-server", Latency: 10 * time.Millisecond, Success: true},
+			{Command: "docker logs", Response: "starting up", IsFallback: true},
+		},
+	})
+
+	s := NewDockerCommandService()
+	s.commands = map[string]MockCommand{}
+	s.fallbackResponses = map[string]string{}
+
+	if err := s.LoadCassette(path); err != nil {
+		t.Fatalf("LoadCassette returned error: %v", err)
+	}
+
+	output, err := s.ExecuteCommand("docker ps")
+	if err != nil {
+		t.Fatalf("ExecuteCommand(docker ps) returned error: %v", err)
+	}
+	if output != "CONTAINER ID\nweb-server" {
+		t.Fatalf("ExecuteCommand(docker ps) = %q, want the seeded fixture", output)
+	}
+
+	output, err = s.ExecuteCommand("docker logs anything")
+	if err != nil {
+		t.Fatalf("ExecuteCommand(docker logs anything) returned error: %v", err)
+	}
+	if output != "starting up" {
+		t.Fatalf("ExecuteCommand(docker logs anything) = %q, want the seeded fallback", output)
+	}
+}
+
+func TestLoadCassetteRejectsVersionMismatch(t *testing.T) {
+	path := writeCassette(t, Cassette{Version: cassetteVersion + 1})
+
+	s := NewDockerCommandService()
+	if err := s.LoadCassette(path); err == nil {
+		t.Fatalf("LoadCassette with mismatched version = nil error, want an error")
+	}
+}
+
+func TestLoadCassetteMissingFile(t *testing.T) {
+	s := NewDockerCommandService()
+	if err := s.LoadCassette(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("LoadCassette with missing file = nil error, want an error")
+	}
+}
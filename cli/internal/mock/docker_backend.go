@@ -0,0 +1,311 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// DockerEngineBackend implements ContainerBackend against a real Docker
+// daemon over its HTTP API, the way lazydocker does. Unlike
+// DockerCommandService it talks to whatever containers, networks, and
+// images actually exist on the host.
+type DockerEngineBackend struct {
+	cli *client.Client
+}
+
+// NewDockerEngineBackend connects to the Docker daemon described by the
+// standard DOCKER_HOST / DOCKER_* environment variables.
+func NewDockerEngineBackend() (*DockerEngineBackend, error) {
+	return NewDockerEngineBackendForHost("")
+}
+
+// NewDockerEngineBackendForHost connects to the daemon at host (e.g.
+// "tcp://prod-eu.example.com:2376"). An empty host falls back to the
+// standard DOCKER_HOST / DOCKER_* environment variables, the same as
+// NewDockerEngineBackend.
+func NewDockerEngineBackendForHost(host string) (*DockerEngineBackend, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to docker daemon at %q: %w", host, err)
+	}
+	return &DockerEngineBackend{cli: cli}, nil
+}
+
+// ListContainers implements ContainerBackend.
+func (b *DockerEngineBackend) ListContainers(all bool) (string, error) {
+	containers, err := b.cli.ContainerList(context.Background(), types.ContainerListOptions{All: all})
+	if err != nil {
+		return "", fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var lines []string
+	lines = append(lines, "CONTAINER ID\tIMAGE\tSTATUS\tNAMES")
+	for _, c := range containers {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", c.ID[:12], c.Image, c.Status, strings.Join(c.Names, ",")))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Logs implements ContainerBackend.
+func (b *DockerEngineBackend) Logs(containerName string) (string, error) {
+	reader, err := b.cli.ContainerLogs(context.Background(), containerName, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch logs for %s: %w", containerName, err)
+	}
+	defer reader.Close()
+
+	var sb strings.Builder
+	buf := make([]byte, 4096)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			sb.Write(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	return sb.String(), nil
+}
+
+// ListNetworks implements ContainerBackend.
+func (b *DockerEngineBackend) ListNetworks() (string, error) {
+	networks, err := b.cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	var lines []string
+	lines = append(lines, "NETWORK ID\tNAME\tDRIVER\tSCOPE")
+	for _, n := range networks {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", n.ID[:12], n.Name, n.Driver, n.Scope))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// InspectNetwork implements ContainerBackend.
+func (b *DockerEngineBackend) InspectNetwork(networkName string) (string, error) {
+	info, err := b.cli.NetworkInspect(context.Background(), networkName, types.NetworkInspectOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect network %s: %w", networkName, err)
+	}
+
+	var names []string
+	for _, c := range info.Containers {
+		names = append(names, c.Name)
+	}
+	return fmt.Sprintf("network %s (%s): driver=%s containers=[%s]", info.Name, info.ID, info.Driver, strings.Join(names, ", ")), nil
+}
+
+// Start implements ContainerBackend.
+func (b *DockerEngineBackend) Start(containerName string) (string, error) {
+	if err := b.cli.ContainerStart(context.Background(), containerName, types.ContainerStartOptions{}); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", containerName, err)
+	}
+	return containerName, nil
+}
+
+// Restart implements ContainerBackend.
+func (b *DockerEngineBackend) Restart(containerName string) (string, error) {
+	if err := b.cli.ContainerRestart(context.Background(), containerName, container.StopOptions{}); err != nil {
+		return "", fmt.Errorf("failed to restart %s: %w", containerName, err)
+	}
+	return containerName, nil
+}
+
+// Stop implements ContainerBackend.
+func (b *DockerEngineBackend) Stop(containerName string) (string, error) {
+	if err := b.cli.ContainerStop(context.Background(), containerName, container.StopOptions{}); err != nil {
+		return "", fmt.Errorf("failed to stop %s: %w", containerName, err)
+	}
+	return containerName, nil
+}
+
+// ConnectNetwork implements ContainerBackend.
+func (b *DockerEngineBackend) ConnectNetwork(networkName, containerName string) (string, error) {
+	if err := b.cli.NetworkConnect(context.Background(), networkName, containerName, &network.EndpointSettings{}); err != nil {
+		return "", fmt.Errorf("failed to connect %s to %s: %w", containerName, networkName, err)
+	}
+	return "", nil
+}
+
+// Images implements ContainerBackend.
+func (b *DockerEngineBackend) Images() (string, error) {
+	images, err := b.cli.ImageList(context.Background(), types.ImageListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list images: %w", err)
+	}
+
+	var lines []string
+	lines = append(lines, "REPOSITORY\tTAG\tIMAGE ID\tSIZE")
+	for _, img := range images {
+		repoTag := "<none>:<none>"
+		if len(img.RepoTags) > 0 {
+			repoTag = img.RepoTags[0]
+		}
+		parts := strings.SplitN(repoTag, ":", 2)
+		repo, tag := parts[0], ""
+		if len(parts) == 2 {
+			tag = parts[1]
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%d", repo, tag, img.ID[:19], img.Size))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// StreamLogs implements LogStreamer against the real Docker Engine API,
+// using ContainerLogs with Follow/Tail/Since set from opts and demuxing
+// the combined stdout/stderr stream with stdcopy the way the Docker CLI
+// itself does.
+func (b *DockerEngineBackend) StreamLogs(ctx context.Context, containerName string, opts LogOptions) (<-chan LogLine, error) {
+	tail := opts.Tail
+	if tail == "" {
+		tail = "all"
+	}
+
+	reader, err := b.cli.ContainerLogs(ctx, containerName, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       tail,
+		Since:      opts.Since,
+		Timestamps: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream logs for %s: %w", containerName, err)
+	}
+
+	stdoutR, stdoutW := io.Pipe()
+	stderrR, stderrW := io.Pipe()
+
+	go func() {
+		defer reader.Close()
+		defer stdoutW.Close()
+		defer stderrW.Close()
+		_, _ = stdcopy.StdCopy(stdoutW, stderrW, reader)
+	}()
+
+	lines := make(chan LogLine)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	scan := func(r io.Reader, stream string) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			ts, content := splitDockerTimestamp(scanner.Text())
+			select {
+			case lines <- LogLine{Stream: stream, Container: containerName, Timestamp: ts, Content: content}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	go scan(stdoutR, "stdout")
+	go scan(stderrR, "stderr")
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	return lines, nil
+}
+
+// splitDockerTimestamp separates the RFC3339Nano timestamp the Docker
+// Engine API prefixes each log line with (requested via Timestamps: true)
+// from its content. If line doesn't start with a parseable timestamp, it
+// is returned as-is with the current time.
+func splitDockerTimestamp(line string) (time.Time, string) {
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) != 2 {
+		return time.Now(), line
+	}
+	ts, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Now(), line
+	}
+	return ts, parts[1]
+}
+
+// DialContainer implements PortProber by resolving container's IP address
+// on the daemon's default bridge (or, failing that, on any one of its
+// attached networks that has an address; which one is not defined when
+// there are several) and attempting a TCP connection to it on port.
+func (b *DockerEngineBackend) DialContainer(ctx context.Context, containerName string, port int, timeout time.Duration) error {
+	info, err := b.cli.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", containerName, err)
+	}
+
+	ip := ""
+	if info.NetworkSettings != nil {
+		ip = info.NetworkSettings.IPAddress
+		if ip == "" {
+			for _, endpoint := range info.NetworkSettings.Networks {
+				if endpoint.IPAddress != "" {
+					ip = endpoint.IPAddress
+					break
+				}
+			}
+		}
+	}
+	if ip == "" {
+		return fmt.Errorf("container %s has no network address", containerName)
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// HealthStatus implements HealthInspector by reading a container's Docker
+// healthcheck status (empty if none is configured).
+func (b *DockerEngineBackend) HealthStatus(ctx context.Context, containerName string) (string, error) {
+	info, err := b.cli.ContainerInspect(ctx, containerName)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %w", containerName, err)
+	}
+	if info.State == nil || info.State.Health == nil {
+		return "", nil
+	}
+	return info.State.Health.Status, nil
+}
+
+// SuggestNextCommand implements ContainerBackend using the same heuristics
+// as DockerCommandService, since the conversational flow is identical
+// whether the backend is mocked or real.
+func (b *DockerEngineBackend) SuggestNextCommand(previousCommand string) string {
+	return (&DockerCommandService{}).SuggestNextCommand(previousCommand)
+}
+
+// SuggestNextCommandWithEvents implements ReactiveSuggester using the
+// same heuristics as DockerCommandService, since the reasoning about
+// what a given event implies doesn't depend on whether the backend is
+// mocked or real.
+func (b *DockerEngineBackend) SuggestNextCommandWithEvents(previousCommand string, events []Event) string {
+	return (&DockerCommandService{}).SuggestNextCommandWithEvents(previousCommand, events)
+}
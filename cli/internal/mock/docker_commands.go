@@ -2,9 +2,13 @@
 package mock
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+
+	"cloud-assist/internal/errdefs"
 )
 
 // MockCommand represents a command and its simulated response
@@ -19,6 +23,7 @@ type MockCommand struct {
 type DockerCommandService struct {
 	commands          map[string]MockCommand
 	fallbackResponses map[string]string
+	interceptors      []Interceptor
 }
 
 // NewDockerCommandService creates a new mock Docker command service with pre-defined commands
@@ -149,8 +154,35 @@ func (s *DockerCommandService) AddFallbackResponse(commandPrefix, response strin
 	s.fallbackResponses[commandPrefix] = response
 }
 
-// ExecuteCommand simulates executing a command and returns the result
+// ExecuteCommand simulates executing a command and returns the result. The
+// command runs through every interceptor registered via Use, in
+// registration order: each PreHook may rewrite or block it before it
+// reaches the fixtures below, and each PostHook (in reverse order, so the
+// first-registered interceptor sees the final result last) may transform
+// the response or error before ExecuteCommand returns it.
 func (s *DockerCommandService) ExecuteCommand(command string) (string, error) {
+	ctx := context.Background()
+
+	for _, interceptor := range s.interceptors {
+		var err error
+		command, err = interceptor.PreHook(ctx, command)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	response, err := s.execute(command)
+
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		response, err = s.interceptors[i].PostHook(ctx, command, response, err)
+	}
+	return response, err
+}
+
+// execute runs command against the fixtures, with no interceptors
+// involved - the part of ExecuteCommand that existed before the
+// interceptor chain was added.
+func (s *DockerCommandService) execute(command string) (string, error) {
 	// Check for exact match
 	if mockCmd, ok := s.commands[command]; ok {
 		// Simulate command execution delay
@@ -159,7 +191,7 @@ func (s *DockerCommandService) ExecuteCommand(command string) (string, error) {
 		if mockCmd.IsSuccess {
 			return mockCmd.Response, nil
 		}
-		return mockCmd.Response, fmt.Errorf("command failed: %s", command)
+		return mockCmd.Response, errdefs.ErrDaemonFault{Cause: fmt.Errorf("command failed: %s", command)}
 	}
 
 	// Check for fallback response
@@ -172,7 +204,116 @@ func (s *DockerCommandService) ExecuteCommand(command string) (string, error) {
 	}
 
 	// Default response for unknown commands
-	return "", fmt.Errorf("unknown command: %s", command)
+	return "", classifyUnknownCommand(command)
+}
+
+// classifyUnknownCommand turns a command line with no matching fixture
+// into the typed errdefs error its verb and argument imply, so the UI
+// can distinguish "no such container" from a command this service
+// simply has no fixture for.
+func classifyUnknownCommand(command string) error {
+	cause := fmt.Errorf("unknown command: %s", command)
+	fields := strings.Fields(command)
+
+	switch {
+	case len(fields) >= 3 && (fields[1] == "logs" || fields[1] == "start" || fields[1] == "restart" || fields[1] == "stop"):
+		return errdefs.ErrContainerNotFound{Name: fields[2], Cause: cause}
+	case len(fields) >= 4 && fields[1] == "network" && fields[2] == "inspect":
+		return errdefs.ErrNetworkNotFound{Name: fields[3], Cause: cause}
+	}
+	return cause
+}
+
+// ListContainers implements ContainerBackend by replaying the fixture for
+// "docker ps" (or "docker ps -a" when all is true).
+func (s *DockerCommandService) ListContainers(all bool) (string, error) {
+	if all {
+		return s.ExecuteCommand("docker ps -a")
+	}
+	return s.ExecuteCommand("docker ps")
+}
+
+// Logs implements ContainerBackend by replaying the fixture for
+// "docker logs <container>".
+func (s *DockerCommandService) Logs(container string) (string, error) {
+	return s.ExecuteCommand("docker logs " + container)
+}
+
+// ListNetworks implements ContainerBackend by replaying the fixture for
+// "docker network ls".
+func (s *DockerCommandService) ListNetworks() (string, error) {
+	return s.ExecuteCommand("docker network ls")
+}
+
+// InspectNetwork implements ContainerBackend by replaying the fixture for
+// "docker network inspect <network>".
+func (s *DockerCommandService) InspectNetwork(network string) (string, error) {
+	return s.ExecuteCommand("docker network inspect " + network)
+}
+
+// Start implements ContainerBackend by replaying the fixture for
+// "docker start <container>".
+func (s *DockerCommandService) Start(container string) (string, error) {
+	return s.ExecuteCommand("docker start " + container)
+}
+
+// Restart implements ContainerBackend by replaying the fixture for
+// "docker restart <container>".
+func (s *DockerCommandService) Restart(container string) (string, error) {
+	return s.ExecuteCommand("docker restart " + container)
+}
+
+// Stop implements ContainerBackend by replaying the fixture for
+// "docker stop <container>".
+func (s *DockerCommandService) Stop(container string) (string, error) {
+	return s.ExecuteCommand("docker stop " + container)
+}
+
+// ConnectNetwork implements ContainerBackend by replaying the fixture for
+// "docker network connect <network> <container>".
+func (s *DockerCommandService) ConnectNetwork(network, container string) (string, error) {
+	return s.ExecuteCommand("docker network connect " + network + " " + container)
+}
+
+// Images implements ContainerBackend by replaying the fixture for
+// "docker images".
+func (s *DockerCommandService) Images() (string, error) {
+	return s.ExecuteCommand("docker images")
+}
+
+// StreamLogs implements LogStreamer by replaying the "docker logs"
+// fixture for container line-by-line, honoring opts.Tail. If opts.Follow
+// is set, the channel stays open (with no further lines) until ctx is
+// canceled, simulating having caught up with a live tail.
+func (s *DockerCommandService) StreamLogs(ctx context.Context, container string, opts LogOptions) (<-chan LogLine, error) {
+	output, err := s.Logs(container)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(output, "\n")
+	if opts.Tail != "" && opts.Tail != "all" {
+		if n, convErr := strconv.Atoi(opts.Tail); convErr == nil && n >= 0 && n < len(lines) {
+			lines = lines[len(lines)-n:]
+		}
+	}
+
+	ch := make(chan LogLine)
+	go func() {
+		defer close(ch)
+		for _, line := range lines {
+			select {
+			case ch <- LogLine{Stream: "stdout", Container: container, Timestamp: time.Now(), Content: line}:
+			case <-ctx.Done():
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if opts.Follow {
+			<-ctx.Done()
+		}
+	}()
+	return ch, nil
 }
 
 // SuggestNextCommand suggests the next command based on previous command
@@ -203,3 +344,23 @@ func (s *DockerCommandService) SuggestNextCommand(previousCommand string) string
 	// Default suggestion
 	return "docker ps"
 }
+
+// SuggestNextCommandWithEvents implements ReactiveSuggester: it checks
+// events (most recent last) for anything that should override the static
+// SuggestNextCommand heuristic - an exited or oom container promotes
+// investigating that specific container, a new network promotes
+// inspecting it - and otherwise falls back to SuggestNextCommand.
+func (s *DockerCommandService) SuggestNextCommandWithEvents(previousCommand string, events []Event) string {
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		switch {
+		case event.Type == "container" && event.Action == "oom":
+			return fmt.Sprintf("docker inspect --format '{{.State}}' %s", event.Actor)
+		case event.Type == "container" && event.Action == "exited":
+			return fmt.Sprintf("docker logs %s", event.Actor)
+		case event.Type == "network" && event.Action == "create":
+			return fmt.Sprintf("docker network inspect %s", event.Actor)
+		}
+	}
+	return s.SuggestNextCommand(previousCommand)
+}
@@ -0,0 +1,156 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// DockerRuntime implements ContainerRuntime against the Docker Engine
+// API. It embeds DockerEngineBackend to get the ContainerBackend half of
+// the interface for free, and adds the capability naming/parsing and
+// portable operations ContainerRuntime layers on top.
+type DockerRuntime struct {
+	*DockerEngineBackend
+}
+
+// NewDockerRuntime connects to the Docker daemon described by the
+// standard DOCKER_HOST / DOCKER_* environment variables.
+func NewDockerRuntime() (*DockerRuntime, error) {
+	backend, err := NewDockerEngineBackend()
+	if err != nil {
+		return nil, err
+	}
+	return &DockerRuntime{DockerEngineBackend: backend}, nil
+}
+
+// Name implements ContainerRuntime.
+func (r *DockerRuntime) Name() string { return "docker" }
+
+// CommandFor implements ContainerRuntime.
+func (r *DockerRuntime) CommandFor(capability Capability, args ...string) string {
+	switch capability {
+	case CapabilityListContainers:
+		return "docker ps"
+	case CapabilityListAllContainers:
+		return "docker ps -a"
+	case CapabilityLogs:
+		return fmt.Sprintf("docker logs %s", args[0])
+	case CapabilityStart:
+		return fmt.Sprintf("docker start %s", args[0])
+	case CapabilityRestart:
+		return fmt.Sprintf("docker restart %s", args[0])
+	case CapabilityStop:
+		return fmt.Sprintf("docker stop %s", args[0])
+	case CapabilityNetworkList:
+		return "docker network ls"
+	case CapabilityNetworkInspect:
+		return fmt.Sprintf("docker network inspect %s", args[0])
+	case CapabilityNetworkConnect:
+		return fmt.Sprintf("docker network connect %s %s", args[0], args[1])
+	case CapabilityImages:
+		return "docker images"
+	default:
+		return ""
+	}
+}
+
+// Parse implements ContainerRuntime, the inverse of CommandFor.
+func (r *DockerRuntime) Parse(command string) (Capability, []string, bool) {
+	parts := strings.Fields(command)
+	switch {
+	case command == "docker ps":
+		return CapabilityListContainers, nil, true
+	case command == "docker ps -a":
+		return CapabilityListAllContainers, nil, true
+	case command == "docker images":
+		return CapabilityImages, nil, true
+	case command == "docker network ls":
+		return CapabilityNetworkList, nil, true
+	case len(parts) >= 3 && parts[0] == "docker" && parts[1] == "logs":
+		return CapabilityLogs, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "docker" && parts[1] == "start":
+		return CapabilityStart, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "docker" && parts[1] == "restart":
+		return CapabilityRestart, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "docker" && parts[1] == "stop":
+		return CapabilityStop, parts[2:3], true
+	case len(parts) >= 4 && parts[0] == "docker" && parts[1] == "network" && parts[2] == "inspect":
+		return CapabilityNetworkInspect, parts[3:4], true
+	case len(parts) >= 5 && parts[0] == "docker" && parts[1] == "network" && parts[2] == "connect":
+		return CapabilityNetworkConnect, parts[3:5], true
+	default:
+		return "", nil, false
+	}
+}
+
+// NetworkAttach implements ContainerRuntime as a capability-named alias
+// for ConnectNetwork.
+func (r *DockerRuntime) NetworkAttach(containerName, network string) error {
+	_, err := r.ConnectNetwork(network, containerName)
+	return err
+}
+
+// Exec implements ContainerRuntime by running args inside container via
+// the Engine API's exec create/attach sequence, the way the Docker CLI's
+// own "docker exec" does.
+func (r *DockerRuntime) Exec(containerName string, args []string) (string, error) {
+	ctx := context.Background()
+	created, err := r.cli.ContainerExecCreate(ctx, containerName, types.ExecConfig{
+		Cmd:          args,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create exec for %s: %w", containerName, err)
+	}
+
+	attached, err := r.cli.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return "", fmt.Errorf("failed to attach exec for %s: %w", containerName, err)
+	}
+	defer attached.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(attached.Reader); err != nil {
+		return "", fmt.Errorf("failed to read exec output for %s: %w", containerName, err)
+	}
+	return buf.String(), nil
+}
+
+// List implements ContainerRuntime, converting ListContainers'
+// preformatted table into portable ContainerConfig values.
+func (r *DockerRuntime) List(all bool) ([]ContainerConfig, error) {
+	output, err := r.ListContainers(all)
+	if err != nil {
+		return nil, err
+	}
+	return parseContainerTable(output), nil
+}
+
+// parseContainerTable turns the "ID\tIMAGE\tSTATUS\tNAMES" table
+// ListContainers renders into ContainerConfig values, skipping the
+// header row. Shared by every ContainerRuntime whose List is built on
+// top of its ContainerBackend table output.
+func parseContainerTable(output string) []ContainerConfig {
+	lines := strings.Split(output, "\n")
+	if len(lines) <= 1 {
+		return nil
+	}
+
+	var configs []ContainerConfig
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if len(cols) < 4 {
+			continue
+		}
+		configs = append(configs, ContainerConfig{Name: cols[3], Image: cols[1], Status: cols[2]})
+	}
+	return configs
+}
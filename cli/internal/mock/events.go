@@ -0,0 +1,158 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Event is a runtime-neutral notification that something happened to a
+// container, network, or image - the same portable shape ContainerConfig
+// gives List, so callers never parse a runtime's native event format
+// (Docker Engine API's events.Message, Podman's --format json, ...).
+type Event struct {
+	// Type names what kind of thing the event is about: "container",
+	// "network", or "image".
+	Type string
+	// Action names what happened: "start", "stop", "exited", "oom",
+	// "create", ...
+	Action string
+	// Actor is the name (or ID, if the runtime didn't report one) of
+	// the container/network/image the event concerns.
+	Actor string
+	Time  time.Time
+}
+
+// EventSource is implemented by backends that can stream runtime events
+// as they happen, the same optional-capability pattern LogStreamer uses
+// for log subscriptions: a backend that doesn't support it simply isn't
+// asserted to the interface, rather than every ContainerBackend having to
+// implement a no-op.
+type EventSource interface {
+	Events(ctx context.Context) (<-chan Event, error)
+}
+
+// ReactiveSuggester is implemented by backends whose next-command
+// suggestion can take recent events into account, e.g. promoting
+// "docker logs app" after an exited event on app instead of the static
+// heuristic SuggestNextCommand uses.
+type ReactiveSuggester interface {
+	SuggestNextCommandWithEvents(previousCommand string, events []Event) string
+}
+
+// MockEventSource replays Scripted on Interval (default one second),
+// closing the channel once every event has been sent, so UI and
+// AgentService tests can exercise the event subsystem deterministically
+// instead of depending on a live daemon. events_test.go drives
+// AgentService with it; no UI test uses it.
+type MockEventSource struct {
+	Scripted []Event
+	Interval time.Duration
+}
+
+// Events implements EventSource by replaying m.Scripted in order.
+func (m MockEventSource) Events(ctx context.Context) (<-chan Event, error) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		for _, event := range m.Scripted {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// eventWindow is how many of the most recently seen events SubscribeEvents
+// keeps around to hand to a ReactiveSuggester.
+const eventWindow = 10
+
+// SubscribeEvents streams runtime events as they arrive, emitting one
+// TypeEvent AgentMessage per event. If the active backend also
+// implements ReactiveSuggester, each event is folded into a rolling
+// window of the last eventWindow events and used to refresh the pending
+// command suggestion - the same "suggest what to do next" role
+// SuggestNextCommand plays after a one-shot command, but reacting to
+// what's happening on the host instead of only what the user last typed.
+//
+// SubscribeEvents returns an error if the active backend doesn't
+// implement EventSource rather than silently producing no events.
+func (a *AgentService) SubscribeEvents(ctx context.Context) (<-chan AgentMessage, error) {
+	backend := a.backend
+	if a.commandHost != "" && a.commandHost != aggregateHost && a.hosts != nil {
+		if hostBackend, err := a.hosts.Backend(a.commandHost); err == nil {
+			backend = hostBackend
+		}
+	}
+
+	source, ok := backend.(EventSource)
+	if !ok {
+		return nil, fmt.Errorf("backend does not support streaming events")
+	}
+
+	events, err := source.Events(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggester, _ := backend.(ReactiveSuggester)
+	host := a.commandHost
+	messages := make(chan AgentMessage)
+	go func() {
+		defer close(messages)
+
+		var recent []Event
+		for event := range events {
+			select {
+			case messages <- AgentMessage{
+				Type:    TypeEvent,
+				Content: fmt.Sprintf("%s %s: %s", event.Type, event.Action, event.Actor),
+				Time:    event.Time,
+				Host:    host,
+			}:
+			case <-ctx.Done():
+				return
+			}
+
+			if suggester == nil {
+				continue
+			}
+
+			recent = append(recent, event)
+			if len(recent) > eventWindow {
+				recent = recent[len(recent)-eventWindow:]
+			}
+
+			suggestion := suggester.SuggestNextCommandWithEvents(a.previousCommand, recent)
+			if suggestion == "" {
+				continue
+			}
+			a.previousCommand = suggestion
+			select {
+			case messages <- AgentMessage{
+				Type:    TypeCommand,
+				Content: suggestion,
+				Time:    event.Time,
+				Host:    host,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messages, nil
+}
@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// reactiveMockBackend combines DockerCommandService (for ContainerBackend
+// and ReactiveSuggester) with a MockEventSource (for EventSource), the way
+// a real backend would implement all three against one daemon connection -
+// letting SubscribeEvents exercise its full event-to-suggestion path
+// against scripted, deterministic events instead of a live daemon.
+type reactiveMockBackend struct {
+	*DockerCommandService
+	MockEventSource
+}
+
+func TestSubscribeEventsPromotesSuggestionFromScriptedEvents(t *testing.T) {
+	backend := &reactiveMockBackend{
+		DockerCommandService: NewDockerCommandService(),
+		MockEventSource: MockEventSource{
+			Interval: time.Millisecond,
+			Scripted: []Event{
+				{Type: "container", Action: "exited", Actor: "app", Time: time.Unix(0, 0)},
+			},
+		},
+	}
+
+	agent := NewAgentService(backend)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	messages, err := agent.SubscribeEvents(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeEvents returned error: %v", err)
+	}
+
+	var sawEvent, sawSuggestion bool
+	var suggestionContent string
+	for msg := range messages {
+		switch msg.Type {
+		case TypeEvent:
+			sawEvent = true
+		case TypeCommand:
+			sawSuggestion = true
+			suggestionContent = msg.Content
+		}
+	}
+
+	if !sawEvent {
+		t.Fatalf("SubscribeEvents never emitted a TypeEvent message for the scripted event")
+	}
+	if !sawSuggestion {
+		t.Fatalf("SubscribeEvents never emitted a promoted TypeCommand suggestion")
+	}
+	if want := "docker logs app"; suggestionContent != want {
+		t.Fatalf("promoted suggestion = %q, want %q", suggestionContent, want)
+	}
+}
+
+func TestSubscribeEventsRequiresEventSource(t *testing.T) {
+	agent := NewAgentService(NewDockerCommandService())
+
+	if _, err := agent.SubscribeEvents(context.Background()); err == nil {
+		t.Fatalf("SubscribeEvents with a backend lacking EventSource = nil error, want an error")
+	}
+}
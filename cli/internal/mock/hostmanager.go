@@ -0,0 +1,225 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DockerContext describes one named endpoint from the Docker CLI's context
+// store (~/.docker/contexts/meta/<sha256(name)>/meta.json).
+type DockerContext struct {
+	Name string
+	Host string
+}
+
+// dockerContextMeta mirrors the subset of meta.json this package reads.
+type dockerContextMeta struct {
+	Name      string `json:"Name"`
+	Endpoints struct {
+		Docker struct {
+			Host string `json:"Host"`
+		} `json:"docker"`
+	} `json:"Endpoints"`
+}
+
+// BackendFactory builds a ContainerBackend bound to the given Docker host
+// address ("" selects the local daemon via DOCKER_HOST).
+type BackendFactory func(host string) (ContainerBackend, error)
+
+// HostManager loads the Docker CLI's context store and lets a single
+// conversation address multiple hosts, building and caching one
+// ContainerBackend per context on demand.
+type HostManager struct {
+	factory  BackendFactory
+	contexts map[string]DockerContext
+	current  string
+
+	mu       sync.Mutex
+	backends map[string]ContainerBackend
+}
+
+// NewHostManager loads contexts from the default Docker CLI config
+// directory ($DOCKER_CONFIG, or ~/.docker) and returns a HostManager that
+// builds backends via factory.
+func NewHostManager(factory BackendFactory) (*HostManager, error) {
+	dir, err := dockerConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewHostManagerFromDir(dir, factory)
+}
+
+// NewHostManagerFromDir loads contexts from configDir/contexts/meta/*/meta.json,
+// mirroring the layout the Docker CLI itself writes.
+func NewHostManagerFromDir(configDir string, factory BackendFactory) (*HostManager, error) {
+	hm := &HostManager{
+		factory:  factory,
+		contexts: make(map[string]DockerContext),
+		backends: make(map[string]ContainerBackend),
+	}
+
+	metaDir := filepath.Join(configDir, "contexts", "meta")
+	entries, err := os.ReadDir(metaDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hm, nil
+		}
+		return nil, fmt.Errorf("failed to read docker context store: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(metaDir, entry.Name(), "meta.json"))
+		if err != nil {
+			continue
+		}
+		var meta dockerContextMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			continue
+		}
+		if meta.Name == "" {
+			continue
+		}
+		hm.contexts[meta.Name] = DockerContext{Name: meta.Name, Host: meta.Endpoints.Docker.Host}
+	}
+
+	return hm, nil
+}
+
+// dockerConfigDir resolves $DOCKER_CONFIG, falling back to ~/.docker.
+func dockerConfigDir() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".docker"), nil
+}
+
+// Contexts lists the names of every configured context, sorted.
+func (hm *HostManager) Contexts() []string {
+	names := make([]string, 0, len(hm.contexts))
+	for name := range hm.contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasContext reports whether name is a known context.
+func (hm *HostManager) HasContext(name string) bool {
+	_, ok := hm.contexts[name]
+	return ok
+}
+
+// UseContext switches the active context to name, building and caching its
+// backend if this is the first use.
+func (hm *HostManager) UseContext(name string) error {
+	if _, err := hm.Backend(name); err != nil {
+		return err
+	}
+	hm.current = name
+	return nil
+}
+
+// Current returns the name of the active context ("" for the local
+// daemon).
+func (hm *HostManager) Current() string {
+	return hm.current
+}
+
+// Backend returns the (cached) ContainerBackend for the named context,
+// building it via the factory on first use. An empty name returns a
+// backend for the local daemon.
+func (hm *HostManager) Backend(name string) (ContainerBackend, error) {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	if backend, ok := hm.backends[name]; ok {
+		return backend, nil
+	}
+
+	var host string
+	if name != "" {
+		ctx, ok := hm.contexts[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown docker context: %s", name)
+		}
+		host = ctx.Host
+	}
+
+	backend, err := hm.factory(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to context %q: %w", name, err)
+	}
+	hm.backends[name] = backend
+	return backend, nil
+}
+
+// aggregateTimeout bounds how long AggregatePS waits for any single host,
+// borrowed from dozzle's timeout-on-host-info pattern so one unreachable
+// host can't stall the whole fan-out.
+const aggregateTimeout = 3 * time.Second
+
+// AggregatePS runs ListContainers(all) against every configured context
+// concurrently, each bounded by aggregateTimeout, and merges the results
+// into a single host-tagged table.
+func (hm *HostManager) AggregatePS(all bool) string {
+	names := hm.Contexts()
+	type result struct {
+		host   string
+		output string
+		err    error
+	}
+
+	results := make([]result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			done := make(chan result, 1)
+			go func() {
+				backend, err := hm.Backend(name)
+				if err != nil {
+					done <- result{host: name, err: err}
+					return
+				}
+				output, err := backend.ListContainers(all)
+				done <- result{host: name, output: output, err: err}
+			}()
+
+			select {
+			case r := <-done:
+				results[i] = r
+			case <-time.After(aggregateTimeout):
+				results[i] = result{host: name, err: fmt.Errorf("timed out after %s", aggregateTimeout)}
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	var lines []string
+	lines = append(lines, "HOST\tCONTAINERS")
+	for _, r := range results {
+		if r.err != nil {
+			lines = append(lines, fmt.Sprintf("%s\terror: %s", r.host, r.err))
+			continue
+		}
+		for _, line := range strings.Split(r.output, "\n") {
+			lines = append(lines, fmt.Sprintf("%s\t%s", r.host, line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Interceptor hooks into DockerCommandService.ExecuteCommand the way a
+// Powerstrip adapter sits in front of the Docker daemon: PreHook sees a
+// command before it runs and can rewrite or block it, PostHook sees the
+// result afterward and can transform it, without ExecuteCommand's
+// callers (record, the completion provider, AgentService) needing to
+// know interceptors exist at all.
+type Interceptor interface {
+	// PreHook runs before command reaches the fixtures. Returning a
+	// different string rewrites the command (e.g. injecting
+	// "--format json"); returning a non-nil error blocks it entirely,
+	// short-circuiting ExecuteCommand with that error.
+	PreHook(ctx context.Context, command string) (string, error)
+	// PostHook runs after command has executed (or failed to), and may
+	// transform response or err - e.g. redacting secrets, prettifying
+	// JSON, or translating a fixture error into a friendlier message.
+	PostHook(ctx context.Context, command, response string, err error) (string, error)
+}
+
+// Use registers interceptor to run around every future ExecuteCommand
+// call, in registration order for PreHook and reverse order for
+// PostHook - the same "wrap the thing you're decorating" order
+// middleware chains use elsewhere.
+func (s *DockerCommandService) Use(interceptor Interceptor) {
+	s.interceptors = append(s.interceptors, interceptor)
+}
+
+// AuditLogInterceptor appends a line to Path for every command executed,
+// recording when it ran and whether it succeeded, independent of
+// whatever other logging the caller does with the response.
+type AuditLogInterceptor struct {
+	Path string
+}
+
+// NewAuditLogInterceptor returns an AuditLogInterceptor writing to path,
+// creating it (and appending to it across runs) on first use.
+func NewAuditLogInterceptor(path string) *AuditLogInterceptor {
+	return &AuditLogInterceptor{Path: path}
+}
+
+// PreHook implements Interceptor; the audit log only records outcomes, so
+// it never rewrites or blocks a command.
+func (a *AuditLogInterceptor) PreHook(ctx context.Context, command string) (string, error) {
+	return command, nil
+}
+
+// PostHook implements Interceptor, appending one line per command to
+// a.Path. A failure to write the audit log is reported back through err
+// rather than silently swallowed, so "the audit trail is incomplete"
+// isn't hidden from the caller.
+func (a *AuditLogInterceptor) PostHook(ctx context.Context, command, response string, err error) (string, error) {
+	f, openErr := os.OpenFile(a.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if openErr != nil {
+		return response, fmt.Errorf("audit log: %w (original error: %v)", openErr, err)
+	}
+	defer f.Close()
+
+	status := "ok"
+	if err != nil {
+		status = "error: " + err.Error()
+	}
+	fmt.Fprintf(f, "%d\t%s\t%s\n", time.Now().Unix(), command, status)
+	return response, err
+}
+
+// destructiveVerbs names subcommands ConfirmationInterceptor blocks
+// unless Confirm approves them.
+var destructiveVerbs = map[string]bool{
+	"stop": true, "rm": true, "prune": true, "kill": true,
+}
+
+// ConfirmationInterceptor blocks destructive commands (stop, rm, prune,
+// kill) unless Confirm approves them. ExecuteCommand is a synchronous,
+// UI-agnostic CommandService method also called by non-interactive
+// callers (the record subcommand, the tab-completion provider), so
+// rather than returning a bubbletea tea.Cmd - which would tie this
+// package to a specific UI framework and wouldn't fit a function that
+// has to return before any prompt could be answered - confirmation is
+// modeled as an injectable callback, the same pattern
+// auth.SetPassphrasePrompt and ui.SetProgram already use for other
+// prompts that only the program composing the UI knows how to show.
+type ConfirmationInterceptor struct {
+	// Confirm is asked whether to allow command; a nil Confirm blocks
+	// every destructive command outright.
+	Confirm func(command string) bool
+}
+
+// NewConfirmationInterceptor returns a ConfirmationInterceptor that asks
+// confirm before allowing a destructive command through.
+func NewConfirmationInterceptor(confirm func(command string) bool) *ConfirmationInterceptor {
+	return &ConfirmationInterceptor{Confirm: confirm}
+}
+
+// PreHook implements Interceptor, blocking command if its verb is
+// destructive and Confirm doesn't approve it.
+func (c *ConfirmationInterceptor) PreHook(ctx context.Context, command string) (string, error) {
+	fields := strings.Fields(command)
+	if len(fields) < 2 || !destructiveVerbs[fields[1]] {
+		return command, nil
+	}
+	if c.Confirm != nil && c.Confirm(command) {
+		return command, nil
+	}
+	return "", fmt.Errorf("command %q was not confirmed", command)
+}
+
+// PostHook implements Interceptor; confirmation only gates what runs, so
+// it never transforms the result.
+func (c *ConfirmationInterceptor) PostHook(ctx context.Context, command, response string, err error) (string, error) {
+	return response, err
+}
+
+// RateLimitInterceptor blocks commands once Limit of them have run within
+// Window, protecting against a runaway loop (or misbehaving agent)
+// hammering the daemon.
+type RateLimitInterceptor struct {
+	Limit  int
+	Window time.Duration
+
+	mu         sync.Mutex
+	executedAt []time.Time
+}
+
+// NewRateLimitInterceptor returns a RateLimitInterceptor allowing at most
+// limit commands per window.
+func NewRateLimitInterceptor(limit int, window time.Duration) *RateLimitInterceptor {
+	return &RateLimitInterceptor{Limit: limit, Window: window}
+}
+
+// PreHook implements Interceptor, blocking command if the limit has
+// already been reached within the window, and recording this attempt
+// otherwise.
+func (r *RateLimitInterceptor) PreHook(ctx context.Context, command string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-r.Window)
+	kept := r.executedAt[:0:0]
+	for _, t := range r.executedAt {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	r.executedAt = kept
+
+	if len(r.executedAt) >= r.Limit {
+		return "", fmt.Errorf("rate limit exceeded: more than %d commands in %s", r.Limit, r.Window)
+	}
+	r.executedAt = append(r.executedAt, now)
+	return command, nil
+}
+
+// PostHook implements Interceptor; rate limiting only gates what runs, so
+// it never transforms the result.
+func (r *RateLimitInterceptor) PostHook(ctx context.Context, command, response string, err error) (string, error) {
+	return response, err
+}
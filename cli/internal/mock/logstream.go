@@ -0,0 +1,149 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogOptions configures a streaming log subscription.
+type LogOptions struct {
+	Follow bool
+	Tail   string
+	Since  string
+	Grep   string
+}
+
+// LogLine is one line of container log output.
+type LogLine struct {
+	Stream    string // "stdout" or "stderr"
+	Container string
+	Timestamp time.Time
+	Content   string
+}
+
+// LogStreamer is implemented by backends that can stream logs line-by-line
+// instead of returning a single buffered string, the way "docker logs -f"
+// does against the Docker Engine API.
+type LogStreamer interface {
+	StreamLogs(ctx context.Context, container string, opts LogOptions) (<-chan LogLine, error)
+}
+
+// logAnalysisTrigger pairs a keyword seen in streamed log lines with the
+// canned analysis from responseTemplates["log_analysis"] it corresponds
+// to.
+type logAnalysisTrigger struct {
+	keyword  string
+	analysis string
+}
+
+var logAnalysisTriggers = []logAnalysisTrigger{
+	{keyword: "redis", analysis: "connections to the Redis cache failing"},
+	{keyword: "404", analysis: "several HTTP 404 errors that might need attention"},
+	{keyword: "memory", analysis: "potential memory issues based on resource utilization patterns"},
+}
+
+// logAnalysisThreshold is how many lines must match the same trigger
+// before SubscribeLogs surfaces an automatic analysis message.
+const logAnalysisThreshold = 3
+
+// SubscribeLogs streams logs for container as they arrive, emitting one
+// TypeLogLine AgentMessage per line. opts.Grep, if set, filters lines by
+// regex before they're emitted. Once logAnalysisThreshold lines match a
+// known log_analysis pattern, one TypeAgent analysis message is appended
+// automatically, the streaming equivalent of the "after_logs" analysis
+// ExecuteSuggestedCommand triggers for a one-shot "docker logs" call.
+//
+// SubscribeLogs returns an error if the active backend doesn't implement
+// LogStreamer rather than silently falling back to a single buffered
+// read.
+func (a *AgentService) SubscribeLogs(ctx context.Context, container string, opts LogOptions) (<-chan AgentMessage, error) {
+	backend := a.backend
+	if a.commandHost != "" && a.commandHost != aggregateHost && a.hosts != nil {
+		if hostBackend, err := a.hosts.Backend(a.commandHost); err == nil {
+			backend = hostBackend
+		}
+	}
+
+	streamer, ok := backend.(LogStreamer)
+	if !ok {
+		return nil, fmt.Errorf("backend does not support streaming logs")
+	}
+
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		compiled, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep pattern %q: %w", opts.Grep, err)
+		}
+		grep = compiled
+	}
+
+	lines, err := streamer.StreamLogs(ctx, container, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	host := a.commandHost
+	messages := make(chan AgentMessage)
+	go func() {
+		defer close(messages)
+
+		matchCounts := make(map[string]int)
+		analyzed := false
+
+		for line := range lines {
+			if grep != nil && !grep.MatchString(line.Content) {
+				continue
+			}
+
+			select {
+			case messages <- AgentMessage{
+				Type:      TypeLogLine,
+				Content:   line.Content,
+				Time:      line.Timestamp,
+				Host:      host,
+				Stream:    line.Stream,
+				Container: line.Container,
+			}:
+			case <-ctx.Done():
+				return
+			}
+
+			if analyzed {
+				continue
+			}
+
+			lower := strings.ToLower(line.Content)
+			for _, trigger := range logAnalysisTriggers {
+				if !strings.Contains(lower, trigger.keyword) {
+					continue
+				}
+
+				matchCounts[trigger.keyword]++
+				if matchCounts[trigger.keyword] < logAnalysisThreshold {
+					break
+				}
+
+				analyzed = true
+				a.report.RecordFinding(container, trigger.analysis)
+				analysis := AgentMessage{
+					Type:    TypeAgent,
+					Content: fmt.Sprintf("I've analyzed the logs and notice %s. Would you like me to suggest a solution or perform another operation on this container?", trigger.analysis),
+					Time:    time.Now(),
+					Host:    host,
+				}
+				select {
+				case messages <- analysis:
+				case <-ctx.Done():
+					return
+				}
+				break
+			}
+		}
+	}()
+
+	return messages, nil
+}
@@ -0,0 +1,220 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"text/template"
+)
+
+// Notifier delivers a finished SessionReport somewhere, mirroring
+// watchtower's session-report/notifier split: stdout for an interactive
+// terminal, a JSON file or webhook for machine consumption, Slack or
+// email for a human audience.
+type Notifier interface {
+	Notify(report *SessionReport) error
+}
+
+// DefaultReportTemplate renders a SessionReport as a short plain-text
+// summary. Pass a custom template (e.g. loaded from --report-template)
+// to any Notifier that accepts one for a different layout.
+const DefaultReportTemplate = `Cloud-Assist session report ({{len .Entries}} commands, started {{.StartedAt.Format "15:04:05"}})
+{{range .Entries -}}
+- {{.Command}}{{if .Host}} (on {{.Host}}){{end}}: {{if .Err}}FAILED: {{.Err}}{{else}}ok{{end}}{{if .WaitResult}}, verified {{.WaitResult.Strategy}}={{.WaitResult.Success}}{{end}}
+{{range .Findings}}  finding: {{.}}
+{{end -}}
+{{end -}}
+`
+
+// LoadReportTemplate parses a text/template from path, for the
+// --report-template flag.
+func LoadReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report template %s: %w", path, err)
+	}
+	tmpl, err := template.New("report").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// renderReport executes tmpl against report, falling back to
+// DefaultReportTemplate when tmpl is nil.
+func renderReport(tmpl *template.Template, report *SessionReport) (string, error) {
+	if tmpl == nil {
+		var err error
+		tmpl, err = template.New("report").Parse(DefaultReportTemplate)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// StdoutNotifier prints the rendered report to Out (os.Stdout when Out
+// is nil).
+type StdoutNotifier struct {
+	Template *template.Template
+	Out      *os.File
+}
+
+// Notify implements Notifier.
+func (n StdoutNotifier) Notify(report *SessionReport) error {
+	rendered, err := renderReport(n.Template, report)
+	if err != nil {
+		return err
+	}
+	out := n.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, err = fmt.Fprint(out, rendered)
+	return err
+}
+
+// JSONFileNotifier writes the report as indented JSON to Path, for
+// machine consumption (e.g. a CI artifact).
+type JSONFileNotifier struct {
+	Path string
+}
+
+// Notify implements Notifier.
+func (n JSONFileNotifier) Notify(report *SessionReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session report: %w", err)
+	}
+	if err := os.WriteFile(n.Path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write session report to %s: %w", n.Path, err)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the rendered report as the request body to a
+// generic HTTP endpoint.
+type WebhookNotifier struct {
+	URL      string
+	Template *template.Template
+	Client   *http.Client
+}
+
+// Notify implements Notifier.
+func (n WebhookNotifier) Notify(report *SessionReport) error {
+	rendered, err := renderReport(n.Template, report)
+	if err != nil {
+		return err
+	}
+	return postText(n.client(), n.URL, rendered)
+}
+
+func (n WebhookNotifier) client() *http.Client {
+	if n.Client != nil {
+		return n.Client
+	}
+	return http.DefaultClient
+}
+
+// SlackWebhookNotifier posts the rendered report to a Slack incoming
+// webhook, wrapped in the {"text": "..."} payload Slack expects.
+type SlackWebhookNotifier struct {
+	URL      string
+	Template *template.Template
+	Client   *http.Client
+}
+
+// Notify implements Notifier.
+func (n SlackWebhookNotifier) Notify(report *SessionReport) error {
+	rendered, err := renderReport(n.Template, report)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: rendered})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to slack webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// postText is shared by notifiers that just POST a rendered text body.
+func postText(client *http.Client, url, body string) error {
+	resp, err := client.Post(url, "text/plain", bytes.NewBufferString(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier emails the rendered report through an SMTP relay.
+type SMTPNotifier struct {
+	Addr     string // host:port of the SMTP server
+	Auth     smtp.Auth
+	From     string
+	To       []string
+	Subject  string
+	Template *template.Template
+}
+
+// Notify implements Notifier.
+func (n SMTPNotifier) Notify(report *SessionReport) error {
+	rendered, err := renderReport(n.Template, report)
+	if err != nil {
+		return err
+	}
+
+	subject := n.Subject
+	if subject == "" {
+		subject = "Cloud-Assist session report"
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", joinAddrs(n.To))
+	fmt.Fprintf(&msg, "Subject: %s\r\n\r\n", subject)
+	msg.WriteString(rendered)
+
+	if err := smtp.SendMail(n.Addr, n.Auth, n.From, n.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send session report email: %w", err)
+	}
+	return nil
+}
+
+func joinAddrs(addrs []string) string {
+	joined := ""
+	for i, addr := range addrs {
+		if i > 0 {
+			joined += ", "
+		}
+		joined += addr
+	}
+	return joined
+}
@@ -0,0 +1,380 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultPodmanSocket is where rootless Podman exposes its libpod REST
+// API by default (podman system service --user), under the user's
+// runtime directory.
+func defaultPodmanSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir + "/podman/podman.sock"
+	}
+	return "/run/podman/podman.sock"
+}
+
+// PodmanRuntime implements ContainerRuntime against Podman's libpod REST
+// API over its local unix socket, the same API "podman system service"
+// exposes and the one podman-py/podman-compose drive.
+type PodmanRuntime struct {
+	client *http.Client
+	base   string // e.g. "http://d/v4.0.0/libpod"
+}
+
+// NewPodmanRuntime connects to the libpod REST API over the user's
+// Podman socket (respecting PODMAN_SOCKET / XDG_RUNTIME_DIR), the way
+// NewDockerRuntime connects over DOCKER_HOST.
+func NewPodmanRuntime() (*PodmanRuntime, error) {
+	socket := os.Getenv("PODMAN_SOCKET")
+	if socket == "" {
+		socket = defaultPodmanSocket()
+	}
+	if _, err := os.Stat(socket); err != nil {
+		return nil, fmt.Errorf("failed to find podman socket at %s: %w", socket, err)
+	}
+
+	return &PodmanRuntime{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+				},
+			},
+			Timeout: 30 * time.Second,
+		},
+		// libpod's REST API ignores host/scheme on a unix socket, but
+		// net/http still requires a well-formed URL to build requests
+		// against.
+		base: "http://d/v4.0.0/libpod",
+	}, nil
+}
+
+// Name implements ContainerRuntime.
+func (r *PodmanRuntime) Name() string { return "podman" }
+
+// CommandFor implements ContainerRuntime.
+func (r *PodmanRuntime) CommandFor(capability Capability, args ...string) string {
+	switch capability {
+	case CapabilityListContainers:
+		return "podman ps"
+	case CapabilityListAllContainers:
+		return "podman ps -a"
+	case CapabilityLogs:
+		return fmt.Sprintf("podman logs %s", args[0])
+	case CapabilityStart:
+		return fmt.Sprintf("podman start %s", args[0])
+	case CapabilityRestart:
+		return fmt.Sprintf("podman restart %s", args[0])
+	case CapabilityStop:
+		return fmt.Sprintf("podman stop %s", args[0])
+	case CapabilityNetworkList:
+		return "podman network ls"
+	case CapabilityNetworkInspect:
+		return fmt.Sprintf("podman network inspect %s", args[0])
+	case CapabilityNetworkConnect:
+		return fmt.Sprintf("podman network connect %s %s", args[0], args[1])
+	case CapabilityImages:
+		return "podman images"
+	default:
+		return ""
+	}
+}
+
+// Parse implements ContainerRuntime, the inverse of CommandFor.
+func (r *PodmanRuntime) Parse(command string) (Capability, []string, bool) {
+	parts := strings.Fields(command)
+	switch {
+	case command == "podman ps":
+		return CapabilityListContainers, nil, true
+	case command == "podman ps -a":
+		return CapabilityListAllContainers, nil, true
+	case command == "podman images":
+		return CapabilityImages, nil, true
+	case command == "podman network ls":
+		return CapabilityNetworkList, nil, true
+	case len(parts) >= 3 && parts[0] == "podman" && parts[1] == "logs":
+		return CapabilityLogs, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "podman" && parts[1] == "start":
+		return CapabilityStart, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "podman" && parts[1] == "restart":
+		return CapabilityRestart, parts[2:3], true
+	case len(parts) >= 3 && parts[0] == "podman" && parts[1] == "stop":
+		return CapabilityStop, parts[2:3], true
+	case len(parts) >= 4 && parts[0] == "podman" && parts[1] == "network" && parts[2] == "inspect":
+		return CapabilityNetworkInspect, parts[3:4], true
+	case len(parts) >= 5 && parts[0] == "podman" && parts[1] == "network" && parts[2] == "connect":
+		return CapabilityNetworkConnect, parts[3:5], true
+	default:
+		return "", nil, false
+	}
+}
+
+// get issues a GET against the libpod API and decodes the JSON response
+// body into out.
+func (r *PodmanRuntime) get(path string, out interface{}) error {
+	resp, err := r.client.Get(r.base + path)
+	if err != nil {
+		return fmt.Errorf("failed to reach podman socket: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("podman API %s returned status %d", path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// post issues a POST with no body against the libpod API.
+func (r *PodmanRuntime) post(path string) error {
+	resp, err := r.client.Post(r.base+path, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("failed to reach podman socket: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("podman API %s returned status %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+// podmanContainer is the subset of libpod's /containers/json response
+// AgentService cares about.
+type podmanContainer struct {
+	Names  []string `json:"Names"`
+	Image  string   `json:"Image"`
+	Status string   `json:"Status"`
+}
+
+// ListContainers implements ContainerBackend.
+func (r *PodmanRuntime) ListContainers(all bool) (string, error) {
+	var containers []podmanContainer
+	if err := r.get(fmt.Sprintf("/containers/json?all=%t", all), &containers); err != nil {
+		return "", fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	lines := []string{"CONTAINER ID\tIMAGE\tSTATUS\tNAMES"}
+	for _, c := range containers {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", "", c.Image, c.Status, strings.Join(c.Names, ",")))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// List implements ContainerRuntime.
+func (r *PodmanRuntime) List(all bool) ([]ContainerConfig, error) {
+	var containers []podmanContainer
+	if err := r.get(fmt.Sprintf("/containers/json?all=%t", all), &containers); err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var configs []ContainerConfig
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+		configs = append(configs, ContainerConfig{Name: name, Image: c.Image, Status: c.Status})
+	}
+	return configs, nil
+}
+
+// Logs implements ContainerBackend.
+func (r *PodmanRuntime) Logs(container string) (string, error) {
+	resp, err := r.client.Get(fmt.Sprintf("%s/containers/%s/logs?stdout=true&stderr=true", r.base, container))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch logs for %s: %w", container, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to fetch logs for %s: status %d", container, resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs for %s: %w", container, err)
+	}
+	return string(body), nil
+}
+
+// ListNetworks implements ContainerBackend.
+func (r *PodmanRuntime) ListNetworks() (string, error) {
+	var networks []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Driver string `json:"driver"`
+	}
+	if err := r.get("/networks/json", &networks); err != nil {
+		return "", fmt.Errorf("failed to list networks: %w", err)
+	}
+
+	lines := []string{"NETWORK ID\tNAME\tDRIVER\tSCOPE"}
+	for _, n := range networks {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\tlocal", n.ID, n.Name, n.Driver))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// InspectNetwork implements ContainerBackend.
+func (r *PodmanRuntime) InspectNetwork(network string) (string, error) {
+	var info struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Driver     string `json:"driver"`
+		Containers map[string]struct {
+			Name string `json:"name"`
+		} `json:"containers"`
+	}
+	if err := r.get("/networks/"+network+"/json", &info); err != nil {
+		return "", fmt.Errorf("failed to inspect network %s: %w", network, err)
+	}
+
+	var names []string
+	for _, c := range info.Containers {
+		names = append(names, c.Name)
+	}
+	return fmt.Sprintf("network %s (%s): driver=%s containers=[%s]", info.Name, info.ID, info.Driver, strings.Join(names, ", ")), nil
+}
+
+// Start implements ContainerBackend.
+func (r *PodmanRuntime) Start(container string) (string, error) {
+	if err := r.post("/containers/" + container + "/start"); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", container, err)
+	}
+	return container, nil
+}
+
+// Restart implements ContainerBackend.
+func (r *PodmanRuntime) Restart(container string) (string, error) {
+	if err := r.post("/containers/" + container + "/restart"); err != nil {
+		return "", fmt.Errorf("failed to restart %s: %w", container, err)
+	}
+	return container, nil
+}
+
+// Stop implements ContainerBackend.
+func (r *PodmanRuntime) Stop(container string) (string, error) {
+	if err := r.post("/containers/" + container + "/stop"); err != nil {
+		return "", fmt.Errorf("failed to stop %s: %w", container, err)
+	}
+	return container, nil
+}
+
+// ConnectNetwork implements ContainerBackend.
+func (r *PodmanRuntime) ConnectNetwork(network, container string) (string, error) {
+	body, err := json.Marshal(struct {
+		Container string `json:"container"`
+	}{Container: container})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode connect request: %w", err)
+	}
+	resp, err := r.client.Post(r.base+"/networks/"+network+"/connect", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect %s to %s: %w", container, network, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to connect %s to %s: status %d", container, network, resp.StatusCode)
+	}
+	return "", nil
+}
+
+// NetworkAttach implements ContainerRuntime as a capability-named alias
+// for ConnectNetwork.
+func (r *PodmanRuntime) NetworkAttach(container, network string) error {
+	_, err := r.ConnectNetwork(network, container)
+	return err
+}
+
+// Images implements ContainerBackend.
+func (r *PodmanRuntime) Images() (string, error) {
+	var images []struct {
+		RepoTags []string `json:"RepoTags"`
+		ID       string   `json:"Id"`
+		Size     int64    `json:"Size"`
+	}
+	if err := r.get("/images/json", &images); err != nil {
+		return "", fmt.Errorf("failed to list images: %w", err)
+	}
+
+	lines := []string{"REPOSITORY\tTAG\tIMAGE ID\tSIZE"}
+	for _, img := range images {
+		repoTag := "<none>:<none>"
+		if len(img.RepoTags) > 0 {
+			repoTag = img.RepoTags[0]
+		}
+		parts := strings.SplitN(repoTag, ":", 2)
+		repo, tag := parts[0], ""
+		if len(parts) == 2 {
+			tag = parts[1]
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%d", repo, tag, img.ID, img.Size))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Exec implements ContainerRuntime by creating and starting a libpod
+// exec session inside container.
+func (r *PodmanRuntime) Exec(container string, args []string) (string, error) {
+	createBody, err := json.Marshal(struct {
+		Cmd          []string `json:"Cmd"`
+		AttachStdout bool     `json:"AttachStdout"`
+		AttachStderr bool     `json:"AttachStderr"`
+	}{Cmd: args, AttachStdout: true, AttachStderr: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode exec request: %w", err)
+	}
+
+	resp, err := r.client.Post(r.base+"/containers/"+container+"/exec", "application/json", bytes.NewReader(createBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to create exec for %s: %w", container, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to create exec for %s: status %d", container, resp.StatusCode)
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode exec create response: %w", err)
+	}
+
+	startBody, err := json.Marshal(struct{}{})
+	if err != nil {
+		return "", err
+	}
+	startResp, err := r.client.Post(r.base+"/exec/"+created.ID+"/start", "application/json", bytes.NewReader(startBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to start exec for %s: %w", container, err)
+	}
+	defer startResp.Body.Close()
+	if startResp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to start exec for %s: status %d", container, startResp.StatusCode)
+	}
+
+	output, err := io.ReadAll(startResp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read exec output for %s: %w", container, err)
+	}
+	return string(output), nil
+}
+
+// SuggestNextCommand implements ContainerBackend using the same
+// heuristics as DockerCommandService: the conversational flow is
+// identical whether the runtime behind it is Docker or Podman, only the
+// CLI verb differs, so previousCommand's "podman" is swapped for "docker"
+// before asking DockerCommandService and the suggestion's "docker" is
+// swapped back for "podman".
+func (r *PodmanRuntime) SuggestNextCommand(previousCommand string) string {
+	return strings.Replace((&DockerCommandService{}).SuggestNextCommand(strings.Replace(previousCommand, "podman", "docker", 1)), "docker", "podman", 1)
+}
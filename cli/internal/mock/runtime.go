@@ -0,0 +1,80 @@
+package mock
+
+// Capability names one container operation AgentService can suggest -
+// list, logs, start, stop, network attach/inspect, images - independent
+// of the CLI syntax any particular runtime uses to perform it. Keying
+// explanations and command construction by Capability instead of a
+// literal "docker ..." string is what lets the same intent detector in
+// ProcessUserMessage drive Docker, Podman, or Singularity.
+type Capability string
+
+const (
+	// CapabilityListContainers lists running containers.
+	CapabilityListContainers Capability = "list_containers"
+	// CapabilityListAllContainers lists containers including stopped ones.
+	CapabilityListAllContainers Capability = "list_all_containers"
+	// CapabilityLogs fetches a container's logs.
+	CapabilityLogs Capability = "logs"
+	// CapabilityStart starts a stopped container.
+	CapabilityStart Capability = "start"
+	// CapabilityRestart stops and starts a container.
+	CapabilityRestart Capability = "restart"
+	// CapabilityStop stops a running container.
+	CapabilityStop Capability = "stop"
+	// CapabilityNetworkList lists networks.
+	CapabilityNetworkList Capability = "network_list"
+	// CapabilityNetworkInspect inspects one network's configuration.
+	CapabilityNetworkInspect Capability = "network_inspect"
+	// CapabilityNetworkConnect attaches a container to a network.
+	CapabilityNetworkConnect Capability = "network_connect"
+	// CapabilityImages lists locally available images.
+	CapabilityImages Capability = "images"
+)
+
+// ContainerConfig is a runtime-neutral description of one container, the
+// portable shape ContainerRuntime.List returns so callers never parse a
+// runtime's native table output (docker ps columns, podman ps --format
+// json, singularity instance list) to learn what's running.
+type ContainerConfig struct {
+	Name   string
+	Image  string
+	Status string
+}
+
+// ContainerRuntime abstracts the container tool the agent operates
+// against, following the Docker/Singularity executor split in Arvados'
+// crunchrun: the operations the agent suggests - list, logs, start,
+// stop, network attach, exec - are the same regardless of whether the
+// host runs Docker, Podman, or Singularity, so AgentService should
+// depend on this interface rather than hard-coding "docker" anywhere.
+// Embedding ContainerBackend means a ContainerRuntime can be passed
+// anywhere a ContainerBackend is expected today.
+type ContainerRuntime interface {
+	ContainerBackend
+
+	// Name identifies the runtime for display ("docker", "podman",
+	// "singularity"), so agent responses and explanations can name the
+	// tool they're actually about to run instead of assuming Docker.
+	Name() string
+	// CommandFor renders the CLI command for capability against args,
+	// e.g. CommandFor(CapabilityLogs, "web-server") returns
+	// "docker logs web-server" or "podman logs web-server" depending on
+	// the runtime.
+	CommandFor(capability Capability, args ...string) string
+	// Parse recovers the Capability and arguments CommandFor encoded
+	// into command, or ok=false if command isn't one this runtime
+	// produces. It's the inverse of CommandFor, used to dispatch a
+	// suggested command without string-matching "docker" specifically.
+	Parse(command string) (capability Capability, args []string, ok bool)
+	// NetworkAttach connects container to network. It's a
+	// capability-named alias for ConnectNetwork, kept so callers that
+	// think in Capability terms (like AgentService's intent detector)
+	// don't need to know ContainerBackend's Docker-shaped argument order.
+	NetworkAttach(container, network string) error
+	// Exec runs args inside the named container and returns its
+	// combined output.
+	Exec(container string, args []string) (string, error)
+	// List returns a portable description of containers, all also
+	// including stopped ones.
+	List(all bool) ([]ContainerConfig, error)
+}
@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandEntry records one command AgentService executed during a
+// conversation: what was suggested, whether it was approved, how it
+// exited, whether a WaitStrategy verified it, and any log findings it
+// surfaced.
+type CommandEntry struct {
+	Command    string
+	Host       string
+	Approved   bool
+	Output     string
+	Err        string
+	WaitResult *WaitResult
+	Findings   []string
+	Time       time.Time
+}
+
+// SessionReport accumulates every CommandEntry for one conversation, so
+// it can be flushed through a Notifier at session end (or on a timer /
+// SIGINT) for headless/CI runs. Record runs from the main
+// ProcessUserMessage path, RecordFinding from the SubscribeLogs
+// goroutine, and Snapshot from whatever goroutine is about to notify
+// (the SIGINT handler, or the normal return path) - mu guards Entries
+// across all three.
+type SessionReport struct {
+	StartedAt time.Time
+	Entries   []CommandEntry
+
+	mu sync.Mutex
+}
+
+// NewSessionReport starts a new, empty report.
+func NewSessionReport() *SessionReport {
+	return &SessionReport{StartedAt: time.Now()}
+}
+
+// Record appends entry to the report.
+func (r *SessionReport) Record(entry CommandEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Entries = append(r.Entries, entry)
+}
+
+// RecordFinding attaches a log-analysis finding to the most recent entry
+// for container, if one exists.
+func (r *SessionReport) RecordFinding(container, finding string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.Entries) - 1; i >= 0; i-- {
+		entry := &r.Entries[i]
+		if entry.Command == "" {
+			continue
+		}
+		if !containsWord(entry.Command, container) {
+			continue
+		}
+		entry.Findings = append(entry.Findings, finding)
+		return
+	}
+}
+
+// Snapshot returns a point-in-time copy of the report, safe to hand to a
+// Notifier (which json.Marshals or templates over Entries) while Record
+// and RecordFinding keep appending to the live report concurrently.
+func (r *SessionReport) Snapshot() *SessionReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]CommandEntry, len(r.Entries))
+	copy(entries, r.Entries)
+	return &SessionReport{StartedAt: r.StartedAt, Entries: entries}
+}
+
+// containsWord reports whether command names container as one of its
+// space-separated arguments (avoiding a substring false-positive, e.g.
+// "app" shouldn't match "webapp").
+func containsWord(command, container string) bool {
+	for _, word := range strings.Fields(command) {
+		if word == container {
+			return true
+		}
+	}
+	return false
+}
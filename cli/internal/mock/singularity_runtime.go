@@ -0,0 +1,219 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SingularityRuntime implements ContainerRuntime for HPC users by
+// shelling out to the `singularity` CLI, the way Arvados' crunchrun
+// drives Singularity alongside its Docker executor: Singularity has no
+// daemon or REST API to talk to, so instances (its closest equivalent to
+// a running container) are only reachable through `singularity instance
+// list`, `singularity exec`, and friends.
+type SingularityRuntime struct {
+	// binary is the executable to invoke, overridable in tests; "" means
+	// the "singularity" found on PATH.
+	binary string
+}
+
+// NewSingularityRuntime looks for the singularity binary on PATH.
+func NewSingularityRuntime() (*SingularityRuntime, error) {
+	if _, err := exec.LookPath("singularity"); err != nil {
+		return nil, fmt.Errorf("singularity binary not found on PATH: %w", err)
+	}
+	return &SingularityRuntime{}, nil
+}
+
+// Name implements ContainerRuntime.
+func (r *SingularityRuntime) Name() string { return "singularity" }
+
+func (r *SingularityRuntime) run(args ...string) (string, error) {
+	binary := r.binary
+	if binary == "" {
+		binary = "singularity"
+	}
+	cmd := exec.Command(binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s: %w: %s", binary, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// CommandFor implements ContainerRuntime. Singularity has no restart or
+// image-list verb analogous to Docker's, so CapabilityRestart and
+// CapabilityImages fall back to the nearest equivalent (stop+start, and
+// `singularity cache list` for the cached SIF images).
+func (r *SingularityRuntime) CommandFor(capability Capability, args ...string) string {
+	switch capability {
+	case CapabilityListContainers, CapabilityListAllContainers:
+		return "singularity instance list"
+	case CapabilityLogs:
+		return fmt.Sprintf("singularity instance logs %s", args[0])
+	case CapabilityStart:
+		return fmt.Sprintf("singularity instance start %s", args[0])
+	case CapabilityRestart:
+		return fmt.Sprintf("singularity instance stop %s && singularity instance start %s", args[0], args[0])
+	case CapabilityStop:
+		return fmt.Sprintf("singularity instance stop %s", args[0])
+	case CapabilityNetworkList, CapabilityNetworkInspect, CapabilityNetworkConnect:
+		return fmt.Sprintf("singularity exec instance://%s ip addr", firstArg(args))
+	case CapabilityImages:
+		return "singularity cache list"
+	default:
+		return ""
+	}
+}
+
+// firstArg returns args[0], or "" if args is empty, for CommandFor
+// branches that degrade gracefully without a container name.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// Parse implements ContainerRuntime, the inverse of CommandFor.
+func (r *SingularityRuntime) Parse(command string) (Capability, []string, bool) {
+	parts := strings.Fields(command)
+	switch {
+	case command == "singularity instance list":
+		return CapabilityListContainers, nil, true
+	case command == "singularity cache list":
+		return CapabilityImages, nil, true
+	case len(parts) >= 4 && parts[0] == "singularity" && parts[1] == "instance" && parts[2] == "logs":
+		return CapabilityLogs, parts[3:4], true
+	case len(parts) >= 4 && parts[0] == "singularity" && parts[1] == "instance" && parts[2] == "start":
+		return CapabilityStart, parts[3:4], true
+	case len(parts) >= 4 && parts[0] == "singularity" && parts[1] == "instance" && parts[2] == "stop" && strings.Contains(command, "&&"):
+		return CapabilityRestart, parts[3:4], true
+	case len(parts) >= 4 && parts[0] == "singularity" && parts[1] == "instance" && parts[2] == "stop":
+		return CapabilityStop, parts[3:4], true
+	default:
+		return "", nil, false
+	}
+}
+
+// ListContainers implements ContainerBackend using `singularity instance
+// list`, whose columns (INSTANCE NAME, PID, IP, IMAGE) only loosely
+// match Docker's; all is ignored since Singularity instances have no
+// stopped state to filter out.
+func (r *SingularityRuntime) ListContainers(all bool) (string, error) {
+	return r.run("instance", "list")
+}
+
+// List implements ContainerRuntime by parsing `singularity instance
+// list`'s tabular output into portable ContainerConfig values.
+func (r *SingularityRuntime) List(all bool) ([]ContainerConfig, error) {
+	output, err := r.run("instance", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) <= 1 {
+		return nil, nil
+	}
+
+	var configs []ContainerConfig
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		configs = append(configs, ContainerConfig{Name: fields[0], Image: fields[3], Status: "running"})
+	}
+	return configs, nil
+}
+
+// Logs implements ContainerBackend.
+func (r *SingularityRuntime) Logs(container string) (string, error) {
+	return r.run("instance", "logs", container)
+}
+
+// ListNetworks implements ContainerBackend. Singularity instances share
+// the host's network namespace by default and have no network
+// abstraction of their own, so this reports that instead of an empty
+// table that would otherwise read as "no networks configured".
+func (r *SingularityRuntime) ListNetworks() (string, error) {
+	return "Singularity instances share the host network namespace; there is no separate network list.", nil
+}
+
+// InspectNetwork implements ContainerBackend by reporting the instance's
+// IP address on the host's namespace via `ip addr`, Singularity's
+// closest equivalent to `docker network inspect`.
+func (r *SingularityRuntime) InspectNetwork(network string) (string, error) {
+	return r.run("exec", "instance://"+network, "ip", "addr")
+}
+
+// Start implements ContainerBackend.
+func (r *SingularityRuntime) Start(container string) (string, error) {
+	if _, err := r.run("instance", "start", container, container); err != nil {
+		return "", err
+	}
+	return container, nil
+}
+
+// Restart implements ContainerBackend by stopping then starting the
+// instance, since Singularity has no single restart verb.
+func (r *SingularityRuntime) Restart(container string) (string, error) {
+	if _, err := r.run("instance", "stop", container); err != nil {
+		return "", err
+	}
+	if _, err := r.run("instance", "start", container, container); err != nil {
+		return "", err
+	}
+	return container, nil
+}
+
+// Stop implements ContainerBackend.
+func (r *SingularityRuntime) Stop(container string) (string, error) {
+	if _, err := r.run("instance", "stop", container); err != nil {
+		return "", err
+	}
+	return container, nil
+}
+
+// ConnectNetwork implements ContainerBackend. Singularity instances
+// share the host network namespace, so there is nothing to connect;
+// this reports that instead of silently succeeding.
+func (r *SingularityRuntime) ConnectNetwork(network, container string) (string, error) {
+	return "", fmt.Errorf("singularity instances share the host network namespace and cannot be attached to %s", network)
+}
+
+// NetworkAttach implements ContainerRuntime as a capability-named alias
+// for ConnectNetwork.
+func (r *SingularityRuntime) NetworkAttach(container, network string) error {
+	_, err := r.ConnectNetwork(network, container)
+	return err
+}
+
+// Images implements ContainerBackend by listing cached SIF images.
+func (r *SingularityRuntime) Images() (string, error) {
+	return r.run("cache", "list")
+}
+
+// Exec implements ContainerRuntime using `singularity exec instance://name`.
+func (r *SingularityRuntime) Exec(container string, args []string) (string, error) {
+	return r.run(append([]string{"exec", "instance://" + container}, args...)...)
+}
+
+// SuggestNextCommand implements ContainerBackend using the same
+// heuristics as DockerCommandService, swapping in Singularity's verbs
+// for the cases that have a direct equivalent.
+func (r *SingularityRuntime) SuggestNextCommand(previousCommand string) string {
+	switch {
+	case strings.HasPrefix(previousCommand, "singularity instance list"):
+		return "singularity instance logs web-server"
+	case strings.HasPrefix(previousCommand, "singularity instance logs"):
+		return "singularity instance stop web-server"
+	default:
+		return "singularity instance list"
+	}
+}
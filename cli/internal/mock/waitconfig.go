@@ -0,0 +1,134 @@
+package mock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WaitConfigEntry describes how to verify one container's health after
+// start/restart/network-connect.
+type WaitConfigEntry struct {
+	// Strategy is one of "port", "log", "http", or "healthcheck".
+	Strategy       string        `yaml:"strategy"`
+	Port           int           `yaml:"port,omitempty"`
+	Pattern        string        `yaml:"pattern,omitempty"`
+	URL            string        `yaml:"url,omitempty"`
+	ExpectedStatus int           `yaml:"expected_status,omitempty"`
+	Timeout        time.Duration `yaml:"timeout,omitempty"`
+}
+
+// WaitConfig maps container name to its configured wait strategy.
+type WaitConfig map[string]WaitConfigEntry
+
+// defaultWaitTimeout applies when neither a config entry nor its override
+// sets one.
+const defaultWaitTimeout = 10 * time.Second
+
+// defaultWaitConfigPath is where per-container wait strategies are
+// configured, alongside the rest of Cloud-Assist's user config.
+func defaultWaitConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config directory: %w", err)
+	}
+	return filepath.Join(dir, "cloud-assist", "wait.yaml"), nil
+}
+
+// LoadWaitConfig reads per-container wait strategy overrides from path. A
+// missing file is not an error; every container just falls back to its
+// image-inferred default.
+func LoadWaitConfig(path string) (WaitConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return WaitConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read wait config %s: %w", path, err)
+	}
+
+	var cfg WaitConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse wait config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// loadDefaultWaitConfig best-effort loads the user's wait.yaml, falling
+// back to an empty config (image-inferred defaults only) if it can't be
+// resolved or parsed.
+func loadDefaultWaitConfig() WaitConfig {
+	path, err := defaultWaitConfigPath()
+	if err != nil {
+		return WaitConfig{}
+	}
+	cfg, err := LoadWaitConfig(path)
+	if err != nil {
+		return WaitConfig{}
+	}
+	return cfg
+}
+
+// defaultPortsByImage infers a readiness port from common image names
+// when no explicit wait config entry exists.
+var defaultPortsByImage = map[string]int{
+	"redis":    6379,
+	"nginx":    80,
+	"postgres": 5432,
+	"mysql":    3306,
+	"mongo":    27017,
+}
+
+// inferPort guesses a readiness port from a container's name, since
+// ContainerBackend doesn't expose the image it was created from
+// (e.g. "redis-cache" -> 6379).
+func inferPort(container string) (int, bool) {
+	lower := strings.ToLower(container)
+	for image, port := range defaultPortsByImage {
+		if strings.Contains(lower, image) {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// StrategyFor resolves the WaitStrategy to run after starting,
+// restarting, or network-connecting container, preferring an explicit
+// cfg entry over the image-inferred default. ok is false when no
+// strategy could be resolved (e.g. an invalid regex in cfg).
+func (cfg WaitConfig) StrategyFor(container string) (strategy WaitStrategy, timeout time.Duration, ok bool) {
+	if entry, found := cfg[container]; found {
+		timeout = entry.Timeout
+		if timeout == 0 {
+			timeout = defaultWaitTimeout
+		}
+
+		switch entry.Strategy {
+		case "log":
+			pattern, err := regexp.Compile(entry.Pattern)
+			if err != nil {
+				return nil, 0, false
+			}
+			return WaitForLog{Pattern: pattern}, timeout, true
+		case "http":
+			return WaitForHTTP{URL: entry.URL, ExpectedStatus: entry.ExpectedStatus}, timeout, true
+		case "healthcheck":
+			return WaitForHealthcheck{}, timeout, true
+		case "port":
+			return WaitForListeningPort{Port: entry.Port}, timeout, true
+		default:
+			return nil, 0, false
+		}
+	}
+
+	if port, found := inferPort(container); found {
+		return WaitForListeningPort{Port: port}, defaultWaitTimeout, true
+	}
+
+	return WaitForHealthcheck{}, defaultWaitTimeout, true
+}
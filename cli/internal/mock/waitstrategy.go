@@ -0,0 +1,177 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// WaitResult reports the outcome of a single WaitStrategy.
+type WaitResult struct {
+	Strategy string
+	Success  bool
+	TimedOut bool
+	Detail   string
+	Elapsed  time.Duration
+}
+
+// WaitStrategy polls a container for a single readiness condition, the
+// way testcontainers-go's wait package does. Strategies are composable by
+// running several through Wait and combining their results.
+type WaitStrategy interface {
+	// Name identifies the strategy for logging/messages, e.g. "port".
+	Name() string
+	// Check performs one poll attempt, reporting whether the condition is
+	// currently satisfied and a human-readable detail string.
+	Check(ctx context.Context, backend ContainerBackend, container string) (bool, string, error)
+}
+
+// waitPollInterval is how often Wait re-checks a strategy's condition.
+const waitPollInterval = 200 * time.Millisecond
+
+// Wait polls strategy.Check until it succeeds, ctx is canceled, or
+// timeout elapses.
+func Wait(ctx context.Context, backend ContainerBackend, container string, strategy WaitStrategy, timeout time.Duration) WaitResult {
+	start := time.Now()
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, detail, err := strategy.Check(ctx, backend, container)
+		if err == nil && ok {
+			return WaitResult{Strategy: strategy.Name(), Success: true, Detail: detail, Elapsed: time.Since(start)}
+		}
+		if err != nil {
+			detail = err.Error()
+		}
+
+		select {
+		case <-ctx.Done():
+			return WaitResult{Strategy: strategy.Name(), Success: false, Detail: detail, Elapsed: time.Since(start)}
+		case <-deadline:
+			return WaitResult{Strategy: strategy.Name(), Success: false, TimedOut: true, Detail: detail, Elapsed: time.Since(start)}
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForLog waits until a container's logs contain a line matching
+// Pattern.
+type WaitForLog struct {
+	Pattern *regexp.Regexp
+}
+
+// Name implements WaitStrategy.
+func (w WaitForLog) Name() string { return "log" }
+
+// Check implements WaitStrategy.
+func (w WaitForLog) Check(ctx context.Context, backend ContainerBackend, container string) (bool, string, error) {
+	output, err := backend.Logs(container)
+	if err != nil {
+		return false, "", err
+	}
+	if w.Pattern.MatchString(output) {
+		return true, fmt.Sprintf("log line matching %q", w.Pattern.String()), nil
+	}
+	return false, fmt.Sprintf("no log line matching %q yet", w.Pattern.String()), nil
+}
+
+// PortProber is implemented by backends that can check whether a
+// container is accepting TCP connections on a given port.
+type PortProber interface {
+	DialContainer(ctx context.Context, container string, port int, timeout time.Duration) error
+}
+
+// WaitForListeningPort waits until a container is accepting TCP
+// connections on Port.
+type WaitForListeningPort struct {
+	Port int
+}
+
+// Name implements WaitStrategy.
+func (w WaitForListeningPort) Name() string { return "port" }
+
+// Check implements WaitStrategy.
+func (w WaitForListeningPort) Check(ctx context.Context, backend ContainerBackend, container string) (bool, string, error) {
+	prober, ok := backend.(PortProber)
+	if !ok {
+		// The backend has no way to reach the container's network (e.g.
+		// the mock backend has no real container to dial); assume
+		// success so demo flows aren't blocked on an unreachable check.
+		return true, fmt.Sprintf("port %d accepting connections (simulated)", w.Port), nil
+	}
+	if err := prober.DialContainer(ctx, container, w.Port, waitPollInterval); err != nil {
+		return false, fmt.Sprintf("port %d not yet accepting connections", w.Port), nil
+	}
+	return true, fmt.Sprintf("port %d accepting connections", w.Port), nil
+}
+
+// WaitForHTTP waits until a GET request to URL returns ExpectedStatus.
+type WaitForHTTP struct {
+	URL            string
+	ExpectedStatus int
+	Client         *http.Client
+}
+
+// Name implements WaitStrategy.
+func (w WaitForHTTP) Name() string { return "http" }
+
+// Check implements WaitStrategy.
+func (w WaitForHTTP) Check(ctx context.Context, backend ContainerBackend, container string) (bool, string, error) {
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.URL, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("%s not reachable yet", w.URL), nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != w.ExpectedStatus {
+		return false, fmt.Sprintf("%s returned %d, want %d", w.URL, resp.StatusCode, w.ExpectedStatus), nil
+	}
+	return true, fmt.Sprintf("%s returned %d", w.URL, resp.StatusCode), nil
+}
+
+// HealthInspector is implemented by backends that can report a
+// container's Docker healthcheck status.
+type HealthInspector interface {
+	HealthStatus(ctx context.Context, container string) (string, error)
+}
+
+// WaitForHealthcheck waits until a container's Docker healthcheck status
+// becomes "healthy".
+type WaitForHealthcheck struct{}
+
+// Name implements WaitStrategy.
+func (w WaitForHealthcheck) Name() string { return "healthcheck" }
+
+// Check implements WaitStrategy.
+func (w WaitForHealthcheck) Check(ctx context.Context, backend ContainerBackend, container string) (bool, string, error) {
+	inspector, ok := backend.(HealthInspector)
+	if !ok {
+		return true, "no healthcheck configured (simulated)", nil
+	}
+
+	status, err := inspector.HealthStatus(ctx, container)
+	if err != nil {
+		return false, "", err
+	}
+	if status == "" {
+		return true, "container has no healthcheck configured", nil
+	}
+	if status == "healthy" {
+		return true, "healthcheck reports healthy", nil
+	}
+	return false, fmt.Sprintf("healthcheck status: %s", status), nil
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+
+	"cloud-assist/internal/conversation"
+)
+
+// BranchRef identifies a conversation tree and the tip node of one of its
+// branches, the selection a branch picker hands back to the caller.
+type BranchRef struct {
+	TreeID string
+	NodeID string
+}
+
+// NewBranchPicker builds a SelectModel listing every branch of every
+// persisted conversation, most recently updated tree first, so the TUI can
+// offer "resume this thread" at startup the way lmcli's `view` command
+// lists branches to reopen.
+func NewBranchPicker(store *conversation.Store, width, height int) (SelectModel, error) {
+	trees, err := store.List()
+	if err != nil {
+		return SelectModel{}, err
+	}
+
+	var items []SelectItem
+	for _, tree := range trees {
+		tips, err := store.Branches(tree.ID)
+		if err != nil {
+			continue
+		}
+		for _, tip := range tips {
+			items = append(items, SelectItem{
+				Title:       tree.Title,
+				Description: fmt.Sprintf("branch %s • updated %s", shortNodeID(tip.ID), tree.UpdatedAt.Format("2006-01-02 15:04")),
+				Value:       BranchRef{TreeID: tree.ID, NodeID: tip.ID},
+			})
+		}
+	}
+
+	return NewSelect("Conversations", items, width, height), nil
+}
+
+// shortNodeID trims a node ID down to a label short enough for a list
+// description or the status bar.
+func shortNodeID(id string) string {
+	if len(id) > 8 {
+		return id[len(id)-8:]
+	}
+	return id
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Buffer is a self-contained panel that AppModel can cycle through. It owns
+// its own input handling and rendering, so new buffer types (logs,
+// tool-output, ...) can be added without touching main.go.
+type Buffer interface {
+	Name() string
+	Init() tea.Cmd
+	Update(msg tea.Msg) (Buffer, tea.Cmd)
+	// HandleInput processes a key message, reporting whether it was
+	// consumed (see the HandleInput convention used across ui/).
+	HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd)
+	// ValidateInput reports whether value is acceptable input for this
+	// buffer (e.g. non-empty for Chat), used before submitting it.
+	ValidateInput(value string) error
+	View() string
+	// LogToFile enables or disables appending rendered lines to this
+	// buffer's log file under ~/.local/state/cloud-assist/.
+	LogToFile(enabled bool)
+	Persisting() bool
+}
+
+// BufferBase holds the plumbing shared by every concrete Buffer: a
+// viewport for scrollback and a buffered lines channel. appendLine writes
+// to the viewport and log file directly; nothing sends on or reads from
+// lines.
+type BufferBase struct {
+	name     string
+	viewport viewport.Model
+	lines    chan string
+	persist  bool
+}
+
+// NewBufferBase creates the shared buffer plumbing for a buffer named name.
+func NewBufferBase(name string, width, height int) BufferBase {
+	return BufferBase{
+		name:     name,
+		viewport: viewport.New(width, height),
+		lines:    make(chan string, 64),
+	}
+}
+
+// Name returns the buffer's name, also used as its log file's base name.
+func (b BufferBase) Name() string {
+	return b.name
+}
+
+// LogToFile enables or disables persisting appended lines to disk.
+func (b *BufferBase) LogToFile(enabled bool) {
+	b.persist = enabled
+}
+
+// Persisting reports whether this buffer currently logs to disk.
+func (b BufferBase) Persisting() bool {
+	return b.persist
+}
+
+// appendLine writes a line to the viewport and, if enabled, to this
+// buffer's log file under ~/.local/state/cloud-assist/<name>.log.
+func (b *BufferBase) appendLine(line string) {
+	b.viewport.SetContent(b.viewport.View() + line + "\n")
+	b.viewport.GotoBottom()
+
+	if b.persist {
+		if err := b.writeLogLine(line); err != nil {
+			// Logging failures shouldn't crash the TUI; they're surfaced
+			// the next time the user inspects the log file directly.
+			return
+		}
+	}
+}
+
+func (b *BufferBase) writeLogLine(line string) error {
+	stateDir, err := bufferStateDir()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(stateDir, b.name+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s %s\n", time.Now().Format(time.RFC3339), line)
+	return err
+}
+
+// bufferStateDir returns (creating if necessary) ~/.local/state/cloud-assist.
+func bufferStateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".local", "state", "cloud-assist")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create buffer log directory: %w", err)
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// StatusBuffer shows a scrolling log of application-level status events
+// (connects, disconnects, errors) independent of any one chat session.
+type StatusBuffer struct {
+	BufferBase
+}
+
+// NewStatusBuffer creates a new status buffer.
+func NewStatusBuffer(width, height int) *StatusBuffer {
+	b := &StatusBuffer{BufferBase: NewBufferBase("status", width, height)}
+	b.appendLine(fmt.Sprintf("[%s] cloud-assist started", time.Now().Format("15:04:05")))
+	return b
+}
+
+func (b *StatusBuffer) Init() tea.Cmd { return nil }
+
+func (b *StatusBuffer) Update(msg tea.Msg) (Buffer, tea.Cmd) {
+	var cmd tea.Cmd
+	b.viewport, cmd = b.viewport.Update(msg)
+	return b, cmd
+}
+
+func (b *StatusBuffer) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	b.viewport, cmd = b.viewport.Update(msg)
+	return true, cmd
+}
+
+func (b *StatusBuffer) ValidateInput(value string) error {
+	return errors.New("status buffer does not accept input")
+}
+
+func (b *StatusBuffer) View() string {
+	return b.viewport.View()
+}
+
+// Log records an event line in the status buffer.
+func (b *StatusBuffer) Log(line string) {
+	b.appendLine(fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), line))
+}
+
+// ChatBuffer adapts the existing ChatModel to the Buffer interface so it
+// can live alongside Status and Profile in AppModel's buffer list.
+type ChatBuffer struct {
+	BufferBase
+	chat ChatModel
+}
+
+// NewChatBuffer wraps an existing ChatModel as a buffer.
+func NewChatBuffer(chat ChatModel, width, height int) *ChatBuffer {
+	return &ChatBuffer{
+		BufferBase: NewBufferBase("chat", width, height),
+		chat:       chat,
+	}
+}
+
+func (b *ChatBuffer) Init() tea.Cmd { return b.chat.Init() }
+
+func (b *ChatBuffer) Update(msg tea.Msg) (Buffer, tea.Cmd) {
+	newModel, cmd := b.chat.Update(msg)
+	if chat, ok := newModel.(ChatModel); ok {
+		b.chat = chat
+	}
+	return b, cmd
+}
+
+func (b *ChatBuffer) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	return b.chat.HandleInput(msg)
+}
+
+func (b *ChatBuffer) ValidateInput(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("message cannot be empty")
+	}
+	return nil
+}
+
+func (b *ChatBuffer) View() string {
+	return b.chat.View()
+}
+
+// Chat returns the wrapped chat model for callers (e.g. main.go) that need
+// chat-specific APIs like SetConversation.
+func (b *ChatBuffer) Chat() *ChatModel {
+	return &b.chat
+}
+
+// ProfileBuffer shows the authenticated user's profile / account info.
+type ProfileBuffer struct {
+	BufferBase
+	apiKeyMasked string
+}
+
+// NewProfileBuffer creates a new profile buffer.
+func NewProfileBuffer(width, height int) *ProfileBuffer {
+	b := &ProfileBuffer{BufferBase: NewBufferBase("profile", width, height)}
+	b.refresh()
+	return b
+}
+
+// SetAPIKey updates the masked API key shown in the profile buffer.
+func (b *ProfileBuffer) SetAPIKey(apiKey string) {
+	if len(apiKey) > 4 {
+		b.apiKeyMasked = apiKey[:4] + strings.Repeat("*", len(apiKey)-4)
+	} else {
+		b.apiKeyMasked = strings.Repeat("*", len(apiKey))
+	}
+	b.refresh()
+}
+
+func (b *ProfileBuffer) refresh() {
+	b.viewport.SetContent(fmt.Sprintf("API key: %s\n", b.apiKeyMasked))
+}
+
+func (b *ProfileBuffer) Init() tea.Cmd { return nil }
+
+func (b *ProfileBuffer) Update(msg tea.Msg) (Buffer, tea.Cmd) {
+	var cmd tea.Cmd
+	b.viewport, cmd = b.viewport.Update(msg)
+	return b, cmd
+}
+
+func (b *ProfileBuffer) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	b.viewport, cmd = b.viewport.Update(msg)
+	return true, cmd
+}
+
+func (b *ProfileBuffer) ValidateInput(value string) error {
+	return errors.New("profile buffer does not accept input")
+}
+
+func (b *ProfileBuffer) View() string {
+	return b.viewport.View()
+}
@@ -1,15 +1,47 @@
 package ui
 
 import (
+	"bytes"
 	"cloud-assist/client"
+	"cloud-assist/internal/auth"
+	"cloud-assist/internal/conversation"
+	"cloud-assist/internal/history"
+	"cloud-assist/internal/llm"
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+const (
+	defaultLLMEndpoint = "https://api.openai.com/v1/chat/completions"
+	defaultLLMModel    = "gpt-4o-mini"
+)
+
+// llmEndpoint returns the configured streaming provider endpoint, falling
+// back to defaultLLMEndpoint.
+func llmEndpoint() string {
+	if v := os.Getenv("CLOUD_ASSIST_LLM_ENDPOINT"); v != "" {
+		return v
+	}
+	return defaultLLMEndpoint
+}
+
+// llmModel returns the configured model name, falling back to defaultLLMModel.
+func llmModel() string {
+	if v := os.Getenv("CLOUD_ASSIST_LLM_MODEL"); v != "" {
+		return v
+	}
+	return defaultLLMModel
+}
+
 type messageType int
 
 const (
@@ -19,6 +51,18 @@ const (
 	commandOutput
 	errorMessage
 	retryRequest
+	toolCallSuggestion
+	toolResult
+)
+
+// focusState tracks whether key input goes to the textarea or moves a
+// selection cursor over the transcript, the way a pager's "search mode"
+// steals keys from whatever triggered it.
+type focusState int
+
+const (
+	focusInput focusState = iota
+	focusMessages
 )
 
 type message struct {
@@ -27,6 +71,51 @@ type message struct {
 	command     []string
 	explanation string
 	retryCount  int
+	tool        client.ToolCall
+	toolSuccess bool
+
+	// id identifies a commandSuggestion or toolCallSuggestion message so
+	// its eventual output can be linked back to it instead of appended
+	// as an independent message; hasResult and resultOutput/resultSuccess
+	// hold that linked result once it arrives.
+	id            string
+	hasResult     bool
+	resultOutput  string
+	resultSuccess bool
+}
+
+// sessionMetrics tracks the running totals the footer displays: token
+// usage the server reported on agent replies, wall-clock time since the
+// first prompt, and how many commands/tools the user has approved,
+// rejected, or had to retry.
+type sessionMetrics struct {
+	promptTokens     int
+	completionTokens int
+	startedAt        time.Time
+	approved         int
+	rejected         int
+	retryRounds      int
+}
+
+// addUsage folds usage into the running token totals; a nil usage (the
+// server didn't report one for this reply) leaves the totals unchanged.
+func (s *sessionMetrics) addUsage(usage *client.Usage) {
+	if usage == nil {
+		return
+	}
+	s.promptTokens += usage.PromptTokens
+	s.completionTokens += usage.CompletionTokens
+}
+
+// metricsTickMsg drives the footer's elapsed-time display, refreshed
+// once a second independent of any server traffic.
+type metricsTickMsg time.Time
+
+// tickMetrics schedules the next metricsTickMsg.
+func tickMetrics() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return metricsTickMsg(t)
+	})
 }
 
 // Custom messages for Bubble Tea updates
@@ -34,13 +123,31 @@ type AgentConnectedMsg struct{}
 type AgentDisconnectedMsg struct{}
 type AgentMessageMsg struct {
 	Content string
+	Usage   *client.Usage
+}
+
+// AgentMessageChunkMsg carries one chunk of a streaming agent reply sent
+// over the WebSocket protocol (TypeMessageChunk), the server-backed
+// counterpart to ResponseChunkMsg for the direct llmProvider path.
+type AgentMessageChunkMsg struct {
+	Content string
+}
+
+// AgentMessageEndMsg signals a streaming agent reply (TypeMessageEnd) has
+// finished, so the accumulated buffer can be committed as a single
+// message the same way ResponseEndMsg does for llmProvider. Usage is
+// non-nil when the server reported token accounting for the reply.
+type AgentMessageEndMsg struct {
+	Usage *client.Usage
 }
 type CommandApprovalMsg struct {
 	Command     []string
 	Explanation string
+	ID          string
 }
 type CommandOutputMsg struct {
 	Output string
+	ID     string
 }
 type RetryRequestMsg struct {
 	Content    string
@@ -49,6 +156,37 @@ type RetryRequestMsg struct {
 type AgentErrorMsg struct {
 	Error string
 }
+type ReconnectingMsg struct {
+	Attempt int
+}
+type ToolCallMsg struct {
+	Tool client.ToolCall
+}
+type ToolResultMsg struct {
+	ToolCallID string
+	Output     string
+	Success    bool
+}
+
+// llmStreamStartedMsg carries the channel a streaming LLM reply is being
+// delivered on, once the provider has accepted the request.
+type llmStreamStartedMsg struct {
+	chunks <-chan llm.Chunk
+}
+
+// ResponseChunkMsg carries one token (or token group) of a streaming LLM
+// reply, to be appended to the in-progress assistant message.
+type ResponseChunkMsg struct {
+	Content string
+}
+
+// ResponseEndMsg signals a streaming LLM reply has completed.
+type ResponseEndMsg struct{}
+
+// ResponseErrorMsg signals a streaming LLM reply failed.
+type ResponseErrorMsg struct {
+	Error string
+}
 
 // ChatModel represents the chat interface
 type ChatModel struct {
@@ -67,51 +205,217 @@ type ChatModel struct {
 	agentClient         *client.AgentClient
 	connected           bool
 	messageChannel      chan tea.Msg
+	historyStore        *history.Store
+	conversationID      string
+	convStore           *conversation.Store
+	convTree            *conversation.Tree
+	currentNodeID       string
+	renderer            *Renderer
+	llmProvider         llm.Provider
+	waitingForReply     bool
+	stopSignal          chan struct{}
+	streamCancel        context.CancelFunc
+	chunkChan           <-chan llm.Chunk
+	spinner             SpinnerModel
+	reconnectAttempt    int
+	toolCallMode        bool
+	currentTool         client.ToolCall
+	toolPolicies        map[string]auth.ToolPolicy
+	cursor              cursor.Model
+	focusState          focusState
+	selectedMessage     int
+	messageOffsets      []int
+	messageCache        []string
+	wrap                bool
+	showToolResults     bool
+	metrics             sessionMetrics
 }
 
 // NewChatModel creates a new chat model
 func NewChatModel(width, height int) ChatModel {
-	input := NewMultiline("", "What would you like to do?", width-4, 5)
+	var chatHistory *History
+	if path, err := DefaultHistoryPath(); err == nil {
+		chatHistory, _ = NewHistory(path, 500)
+	}
+	input := NewMultilineWithHistory(chatHistory, "", "What would you like to do?", width-4, 5)
 	vp := viewport.New(width, height-10)
 	vp.Style = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).Padding(1).Border(lipgloss.NormalBorder(), false, true)
 
-	agentClient := client.NewAgentClient("ws://localhost:8765")
+	apiKey, apiKeyErr := auth.GetAPIKey(auth.DefaultProfile)
+	hasAPIKey := apiKeyErr == nil && apiKey != ""
+
+	var agentClientOpts []client.Option
+	if hasAPIKey {
+		agentClientOpts = append(agentClientOpts, client.WithBearerToken(apiKey))
+	}
+	agentClient := client.NewAgentClient("ws://localhost:8765", agentClientOpts...)
 	messageChannel := make(chan tea.Msg, 100)
 
+	var provider llm.Provider
+	if hasAPIKey {
+		provider = llm.NewHTTPProvider(llmEndpoint(), apiKey, llmModel())
+	}
+
+	toolPolicies, err := auth.LoadToolPolicies()
+	if err != nil {
+		toolPolicies = map[string]auth.ToolPolicy{}
+	}
+
+	renderer, _ := NewRenderer(defaultTheme)
+
+	streamCursor := cursor.New()
+	streamCursor.SetChar("▋")
+
 	model := ChatModel{
-		messages:       []message{},
-		viewport:       vp,
-		input:          input,
-		width:          width,
-		height:         height,
-		showInput:      true,
-		agentClient:    agentClient,
-		messageChannel: messageChannel,
+		messages:        []message{},
+		viewport:        vp,
+		input:           input,
+		width:           width,
+		height:          height,
+		showInput:       true,
+		agentClient:     agentClient,
+		messageChannel:  messageChannel,
+		llmProvider:     provider,
+		spinner:         NewSpinner("waiting for reply..."),
+		toolPolicies:    toolPolicies,
+		renderer:        renderer,
+		cursor:          streamCursor,
+		wrap:            true,
+		showToolResults: true,
 	}
+	model.spinner.Stop()
 
 	model.setupWebSocketHandlers()
 	return model
 }
 
+// SetConversation attaches a history store and conversation ID so future
+// messages are persisted, reloading any messages already saved under id.
+func (m *ChatModel) SetConversation(store *history.Store, id string) {
+	m.historyStore = store
+	m.conversationID = id
+
+	if store == nil || id == "" {
+		return
+	}
+
+	conv, err := store.LoadConversation(id)
+	if err != nil {
+		return
+	}
+
+	m.messages = m.messages[:0]
+	for _, stored := range conv.Messages {
+		if msgType, ok := chatMessageType(stored.Role); ok {
+			m.messages = append(m.messages, message{content: stored.Content, msgType: msgType})
+		}
+	}
+	m.updateViewportContent()
+}
+
+// chatMessageType maps a history role back to a chat messageType.
+func chatMessageType(role string) (messageType, bool) {
+	switch role {
+	case "user":
+		return userMessage, true
+	case "agent":
+		return agentMessage, true
+	case "command_output":
+		return commandOutput, true
+	case "error":
+		return errorMessage, true
+	case "tool_result":
+		return toolResult, true
+	}
+	return 0, false
+}
+
+// ConversationID returns the conversation currently being persisted, if any.
+func (m ChatModel) ConversationID() string {
+	return m.conversationID
+}
+
+// SetTheme switches the glamour theme ("dracula", "github", ...) used to
+// syntax-highlight agent messages, command suggestions, and command
+// output, leaving the current theme in place if theme is invalid.
+func (m *ChatModel) SetTheme(theme string) error {
+	renderer, err := NewRenderer(theme)
+	if err != nil {
+		return err
+	}
+	m.renderer = renderer
+	return nil
+}
+
+// SetCompletionProvider attaches p as the source of tab-completion
+// candidates (container names, image refs, network names) for the chat
+// input field. HandleInput consumes tab itself to toggle message focus,
+// so tab pressed in the chat screen does not reach the input field's
+// completion.
+func (m *ChatModel) SetCompletionProvider(p CompletionProvider) {
+	m.input.SetCompletionProvider(p)
+}
+
+// SetConversationTree attaches a branching conversation store, resuming at
+// nodeID (the tip of the branch to continue, or "" for a fresh tree) so
+// future turns are appended as that node's children rather than starting a
+// new thread.
+func (m *ChatModel) SetConversationTree(store *conversation.Store, tree *conversation.Tree, nodeID string) {
+	m.convStore = store
+	m.convTree = tree
+	m.currentNodeID = nodeID
+
+	if store == nil || tree == nil {
+		return
+	}
+	if nodeID != "" {
+		m.agentClient.ResumeConversation(tree.ID)
+	}
+}
+
+// Branch reports the tip node of the active conversation branch, if a
+// branching conversation store is attached, so the status bar can show
+// which branch is active the same way it shows the waiting spinner.
+func (m ChatModel) Branch() (string, bool) {
+	if m.convTree == nil || m.currentNodeID == "" {
+		return "", false
+	}
+	return shortNodeID(m.currentNodeID), true
+}
+
 // setupWebSocketHandlers configures the WebSocket client event handlers
 func (m *ChatModel) setupWebSocketHandlers() {
-	m.agentClient.SetMessageHandler(func(content string) {
+	m.agentClient.SetMessageHandler(func(content string, usage *client.Usage) {
+		select {
+		case m.messageChannel <- AgentMessageMsg{Content: content, Usage: usage}:
+		default:
+		}
+	})
+
+	m.agentClient.SetMessageChunkHandler(func(content string) {
 		select {
-		case m.messageChannel <- AgentMessageMsg{Content: content}:
+		case m.messageChannel <- AgentMessageChunkMsg{Content: content}:
 		default:
 		}
 	})
 
-	m.agentClient.SetCommandApprovalHandler(func(command []string, explanation string) {
+	m.agentClient.SetMessageEndHandler(func(usage *client.Usage) {
 		select {
-		case m.messageChannel <- CommandApprovalMsg{Command: command, Explanation: explanation}:
+		case m.messageChannel <- AgentMessageEndMsg{Usage: usage}:
 		default:
 		}
 	})
 
-	m.agentClient.SetCommandOutputHandler(func(output string) {
+	m.agentClient.SetCommandApprovalHandler(func(command []string, explanation string, id string) {
 		select {
-		case m.messageChannel <- CommandOutputMsg{Output: output}:
+		case m.messageChannel <- CommandApprovalMsg{Command: command, Explanation: explanation, ID: id}:
+		default:
+		}
+	})
+
+	m.agentClient.SetCommandOutputHandler(func(output string, id string) {
+		select {
+		case m.messageChannel <- CommandOutputMsg{Output: output, ID: id}:
 		default:
 		}
 	})
@@ -136,6 +440,27 @@ func (m *ChatModel) setupWebSocketHandlers() {
 		default:
 		}
 	})
+
+	m.agentClient.SetReconnectHandler(func(attempt int) {
+		select {
+		case m.messageChannel <- ReconnectingMsg{Attempt: attempt}:
+		default:
+		}
+	})
+
+	m.agentClient.SetToolCallHandler(func(tool client.ToolCall) {
+		select {
+		case m.messageChannel <- ToolCallMsg{Tool: tool}:
+		default:
+		}
+	})
+
+	m.agentClient.SetToolResultHandler(func(toolCallID string, output string, success bool) {
+		select {
+		case m.messageChannel <- ToolResultMsg{ToolCallID: toolCallID, Output: output, Success: success}:
+		default:
+		}
+	})
 }
 
 // ConnectToAgent attempts to connect to the agent WebSocket server
@@ -162,6 +487,7 @@ func (m ChatModel) Init() tea.Cmd {
 		m.input.Init(),
 		m.ConnectToAgent(),
 		m.listenForWebSocketMessages(),
+		tickMetrics(),
 	)
 }
 
@@ -172,21 +498,185 @@ func (m ChatModel) listenForWebSocketMessages() tea.Cmd {
 	}
 }
 
+// submitUserMessage adds userInput to the transcript and dispatches it to
+// whichever backend is active (direct llmProvider streaming or the
+// AgentClient websocket), the shared tail end of both a normal Enter
+// submission and editSelectedMessage's resubmission of an edited prompt.
+func (m *ChatModel) submitUserMessage(userInput string) tea.Cmd {
+	if m.metrics.startedAt.IsZero() {
+		m.metrics.startedAt = time.Now()
+	}
+	m.addMessage(userInput, userMessage)
+
+	if m.llmProvider != nil {
+		return m.startStreaming(userInput)
+	}
+
+	if m.connected {
+		if err := m.agentClient.SendMessage(userInput); err != nil {
+			m.addMessage(fmt.Sprintf("Error sending message: %v", err), errorMessage)
+			return nil
+		}
+		m.waitingForReply = true
+		m.spinner.Start()
+		return m.cursor.Focus()
+	}
+
+	return nil
+}
+
+// editSelectedMessage opens the selected message's body in $EDITOR. For a
+// prior user message, the edited text truncates everything from that
+// point on and is resubmitted, the way amending an earlier prompt
+// restarts the conversation from there; other message types are edited
+// in place since there's nothing to resubmit them as.
+func (m *ChatModel) editSelectedMessage() tea.Cmd {
+	i := m.selectedMessage
+	msg := m.messages[i]
+
+	edited, err := ExternalEditor(msg.content)
+	if err != nil {
+		m.addMessage(fmt.Sprintf("Error opening editor: %v", err), errorMessage)
+		return nil
+	}
+	edited = strings.TrimRight(edited, "\n")
+
+	if msg.msgType != userMessage {
+		m.messages[i].content = edited
+		m.invalidateMessageCache(i)
+		m.updateViewportContent()
+		return nil
+	}
+
+	m.messages = m.messages[:i]
+	m.focusState = focusInput
+	m.input.Focus()
+	if edited == "" {
+		m.updateViewportContent()
+		return nil
+	}
+	return m.submitUserMessage(edited)
+}
+
+// startStreaming opens a streaming reply from m.llmProvider for userInput,
+// returning a command that dispatches llmStreamStartedMsg once the
+// connection is accepted (or an error). m.stopSignal, closed by ctrl+c,
+// cancels the request while it's in flight.
+func (m *ChatModel) startStreaming(userInput string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := make(chan struct{})
+	m.stopSignal = stop
+	m.streamCancel = cancel
+	m.waitingForReply = true
+	m.spinner.Start()
+	focusCmd := m.cursor.Focus()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	provider := m.llmProvider
+	history := m.llmMessages(userInput)
+
+	return tea.Batch(m.spinner.Init(), focusCmd, func() tea.Msg {
+		chunks, err := provider.Stream(ctx, history)
+		if err != nil {
+			cancel()
+			return ResponseErrorMsg{Error: err.Error()}
+		}
+		return llmStreamStartedMsg{chunks: chunks}
+	})
+}
+
+// endStreaming releases the context startStreaming opened, so its
+// monitor goroutine (blocked on stop/ctx.Done()) exits instead of
+// leaking - called once a streaming reply finishes, errors, or is
+// cancelled, not just on the provider-error path that originally held
+// the only reference to cancel.
+func (m *ChatModel) endStreaming() {
+	if m.streamCancel != nil {
+		m.streamCancel()
+		m.streamCancel = nil
+	}
+	m.stopSignal = nil
+}
+
+// readNextChunk returns a command that reads the next chunk off
+// m.chunkChan, translating it into a ResponseChunkMsg, ResponseEndMsg or
+// ResponseErrorMsg.
+func (m ChatModel) readNextChunk() tea.Cmd {
+	chunks := m.chunkChan
+	return func() tea.Msg {
+		chunk, ok := <-chunks
+		if !ok {
+			return ResponseEndMsg{}
+		}
+		if chunk.Err != nil {
+			return ResponseErrorMsg{Error: chunk.Err.Error()}
+		}
+		return ResponseChunkMsg{Content: chunk.Content}
+	}
+}
+
+// llmMessages builds the message history sent to the LLM provider,
+// appending the user's latest input.
+func (m ChatModel) llmMessages(userInput string) []llm.Message {
+	messages := make([]llm.Message, 0, len(m.messages)+1)
+	for _, msg := range m.messages {
+		switch msg.msgType {
+		case userMessage:
+			messages = append(messages, llm.Message{Role: "user", Content: msg.content})
+		case agentMessage:
+			messages = append(messages, llm.Message{Role: "assistant", Content: msg.content})
+		}
+	}
+	messages = append(messages, llm.Message{Role: "user", Content: userInput})
+	return messages
+}
+
+// Waiting reports whether a streaming reply is currently in flight.
+func (m ChatModel) Waiting() bool {
+	return m.waitingForReply
+}
+
+// Reconnecting reports whether the agent connection was dropped and is
+// being redialed, along with the 1-based attempt number, so the global
+// status bar can show "reconnecting (attempt N)..." the same way it
+// shows the reply spinner.
+func (m ChatModel) Reconnecting() (int, bool) {
+	return m.reconnectAttempt, m.reconnectAttempt > 0
+}
+
+// SpinnerView renders the current spinner frame for callers (e.g. the
+// global status bar) that want to show reply-in-progress feedback.
+func (m ChatModel) SpinnerView() string {
+	return m.spinner.View()
+}
+
 // Update handles chat updates
 func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case metricsTickMsg:
+		cmds = append(cmds, tickMetrics())
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.viewport.Width = msg.Width
 		m.viewport.Height = msg.Height - 10
 		m.input.SetWidth(msg.Width - 4)
+		m.messageCache = nil
 
 	case AgentConnectedMsg:
 		m.connected = true
+		m.reconnectAttempt = 0
 		m.addMessage("How can I help you today?", agentMessage)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
@@ -195,151 +685,371 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.addMessage("Disconnected from agent", errorMessage)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
+	case ReconnectingMsg:
+		m.reconnectAttempt = msg.Attempt
+		cmds = append(cmds, m.listenForWebSocketMessages())
+
 	case AgentMessageMsg:
+		m.metrics.addUsage(msg.Usage)
 		m.addMessage(msg.Content, agentMessage)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
+	case AgentMessageChunkMsg:
+		if !m.waitingForReply || len(m.messages) == 0 || m.messages[len(m.messages)-1].msgType != agentMessage {
+			m.waitingForReply = true
+			m.spinner.Start()
+			m.messages = append(m.messages, message{msgType: agentMessage})
+			cmds = append(cmds, m.cursor.Focus())
+		}
+		m.messages[len(m.messages)-1].content += msg.Content
+		cmds = append(cmds, m.listenForWebSocketMessages())
+
+	case AgentMessageEndMsg:
+		m.metrics.addUsage(msg.Usage)
+		m.waitingForReply = false
+		m.spinner.Stop()
+		m.cursor.Blur()
+		if n := len(m.messages); n > 0 {
+			m.persistMessage(m.messages[n-1].content, agentMessage)
+		}
+		cmds = append(cmds, m.listenForWebSocketMessages())
+
 	case CommandApprovalMsg:
 		m.suggestionMode = true
 		m.currentCommand = msg.Command
 		m.currentExplanation = msg.Explanation
-		m.addCommandSuggestion(msg.Command, msg.Explanation)
+		m.addCommandSuggestion(msg.Command, msg.Explanation, msg.ID)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
 	case CommandOutputMsg:
-		m.addMessage(msg.Output, commandOutput)
+		if !m.resolveCommandOutput(msg.Output, msg.ID) {
+			m.addMessage(msg.Output, commandOutput)
+		} else {
+			m.updateViewportContent()
+		}
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
 	case RetryRequestMsg:
 		m.retryMode = true
 		m.currentRetryContent = msg.Content
 		m.currentRetryCount = msg.RetryCount
+		m.metrics.retryRounds++
 		m.addRetryRequest(msg.Content, msg.RetryCount)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
+	case ToolCallMsg:
+		switch m.toolPolicies[msg.Tool.Name] {
+		case auth.PolicyAlways:
+			m.respondToToolCall(msg.Tool, true)
+			m.addMessage(fmt.Sprintf("Tool %q auto-approved (always)", msg.Tool.Name), commandOutput)
+		case auth.PolicyNever:
+			m.respondToToolCall(msg.Tool, false)
+			m.addMessage(fmt.Sprintf("Tool %q auto-rejected (never)", msg.Tool.Name), commandOutput)
+		default:
+			m.toolCallMode = true
+			m.currentTool = msg.Tool
+			m.addToolCallSuggestion(msg.Tool)
+		}
+		cmds = append(cmds, m.listenForWebSocketMessages())
+
+	case ToolResultMsg:
+		if !m.resolveToolResult(msg.ToolCallID, msg.Output, msg.Success) {
+			m.addToolResult(msg.Output, msg.Success)
+		} else {
+			m.updateViewportContent()
+		}
+		cmds = append(cmds, m.listenForWebSocketMessages())
+
 	case AgentErrorMsg:
 		m.addMessage(fmt.Sprintf("Error: %s", msg.Error), errorMessage)
 		cmds = append(cmds, m.listenForWebSocketMessages())
 
+	case llmStreamStartedMsg:
+		m.chunkChan = msg.chunks
+		m.messages = append(m.messages, message{msgType: agentMessage})
+		cmds = append(cmds, m.readNextChunk())
+
+	case ResponseChunkMsg:
+		if n := len(m.messages); n > 0 {
+			m.messages[n-1].content += msg.Content
+		}
+		cmds = append(cmds, m.readNextChunk())
+
+	case ResponseEndMsg:
+		m.endStreaming()
+		m.waitingForReply = false
+		m.spinner.Stop()
+		m.cursor.Blur()
+		if n := len(m.messages); n > 0 {
+			m.persistMessage(m.messages[n-1].content, agentMessage)
+		}
+
+	case ResponseErrorMsg:
+		m.endStreaming()
+		m.waitingForReply = false
+		m.spinner.Stop()
+		m.cursor.Blur()
+		m.addMessage(fmt.Sprintf("Error: %s", msg.Error), errorMessage)
+
 	case tea.KeyMsg:
-		// Handle global keys first
-		switch msg.String() {
-		case "ctrl+c":
-			if m.suggestionMode || m.retryMode {
-				m.suggestionMode = false
-				m.retryMode = false
-				m.showInput = true
-				m.input.Focus()
+		_, keyCmd := m.HandleInput(msg)
+		cmds = append(cmds, keyCmd)
+	default:
+		if m.waitingForReply {
+			m.spinner, cmd = m.spinner.Update(msg)
+			cmds = append(cmds, cmd)
+			m.cursor, cmd = m.cursor.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		// Update input for non-key messages
+		if m.showInput && !m.suggestionMode && !m.retryMode && !m.toolCallMode {
+			m.input, cmd = m.input.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
 
-				if m.suggestionMode && m.connected {
-					m.agentClient.SendCommandApproval(false)
+	// Always update viewport
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+
+	m.updateViewportContent()
+
+	return m, tea.Batch(cmds...)
+}
+
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed. The app only falls through to
+// global bindings (e.g. quitting from a screen other than chat) when
+// handled is false; ctrl+c in chat is handled here so it cancels an
+// in-flight suggestion/retry instead of always quitting.
+func (m *ChatModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		if m.waitingForReply {
+			if m.stopSignal != nil {
+				close(m.stopSignal)
+			}
+			m.endStreaming()
+			m.waitingForReply = false
+			m.spinner.Stop()
+			m.cursor.Blur()
+			m.addMessage("Request cancelled", errorMessage)
+			return true, nil
+		}
+		if m.suggestionMode || m.retryMode || m.toolCallMode {
+			wasSuggestion, wasRetry, wasToolCall := m.suggestionMode, m.retryMode, m.toolCallMode
+			m.suggestionMode = false
+			m.retryMode = false
+			m.toolCallMode = false
+			m.showInput = true
+			m.input.Focus()
+
+			if wasSuggestion && m.connected {
+				m.agentClient.SendCommandApproval(false)
+			}
+			if wasRetry && m.connected {
+				m.agentClient.SendRetryResponse(false)
+			}
+			if wasToolCall {
+				m.respondToToolCall(m.currentTool, false)
+			}
+			return true, nil
+		}
+		// Nothing in-flight to cancel: let the global ctrl+c quit the app.
+		return false, nil
+
+	case "ctrl+w":
+		m.wrap = !m.wrap
+		m.messageCache = nil
+		m.updateViewportContent()
+		return true, nil
+
+	case "ctrl+t":
+		m.showToolResults = !m.showToolResults
+		m.messageCache = nil
+		m.updateViewportContent()
+		return true, nil
+
+	case "ctrl+x":
+		if m.waitingForReply && m.connected {
+			m.agentClient.SendCancel()
+			m.waitingForReply = false
+			m.spinner.Stop()
+			m.cursor.Blur()
+			m.addMessage("Request cancelled", errorMessage)
+			return true, nil
+		}
+		return false, nil
+
+	case "enter", "ctrl+enter":
+		if m.showInput && !m.suggestionMode && !m.retryMode && !m.toolCallMode && !m.waitingForReply {
+			userInput := strings.TrimSpace(m.input.Value())
+			if userInput != "" {
+				m.input.Submit(userInput)
+				m.input.SetValue("")
+				cmd = m.submitUserMessage(userInput)
+			}
+		}
+		return true, cmd
+
+	case "tab":
+		if m.showInput && !m.suggestionMode && !m.retryMode && !m.toolCallMode {
+			if m.focusState == focusInput {
+				m.focusState = focusMessages
+				m.input.Blur()
+				if m.selectedMessage >= len(m.messages) {
+					m.selectedMessage = len(m.messages) - 1
 				}
-				if m.retryMode && m.connected {
-					m.agentClient.SendRetryResponse(false)
+				if m.selectedMessage < 0 {
+					m.selectedMessage = 0
 				}
-				return m, nil
 			} else {
-				// Exit the application
-				return m, tea.Quit
+				m.focusState = focusInput
+				m.input.Focus()
 			}
+			m.updateViewportContent()
+			return true, nil
+		}
 
-		case "enter":
-			if m.showInput && !m.suggestionMode && !m.retryMode {
-				userInput := strings.TrimSpace(m.input.Value())
-				if userInput != "" && m.connected {
-					m.addMessage(userInput, userMessage)
-					m.input.SetValue("")
-
-					err := m.agentClient.SendMessage(userInput)
-					if err != nil {
-						m.addMessage(fmt.Sprintf("Error sending message: %v", err), errorMessage)
-					}
-				}
-				return m, tea.Batch(cmds...)
-			}
+	case "j", "down":
+		if m.focusState == focusMessages && m.selectedMessage < len(m.messages)-1 {
+			m.selectedMessage++
+			m.updateViewportContent()
+			return true, nil
+		}
 
-		case "ctrl+enter":
-			if m.showInput && !m.suggestionMode && !m.retryMode {
-				userInput := strings.TrimSpace(m.input.Value())
-				if userInput != "" && m.connected {
-					m.addMessage(userInput, userMessage)
-					m.input.SetValue("")
-
-					err := m.agentClient.SendMessage(userInput)
-					if err != nil {
-						m.addMessage(fmt.Sprintf("Error sending message: %v", err), errorMessage)
-					}
-				}
-				return m, tea.Batch(cmds...)
-			}
+	case "k", "up":
+		if m.focusState == focusMessages && m.selectedMessage > 0 {
+			m.selectedMessage--
+			m.updateViewportContent()
+			return true, nil
+		}
 
-		case "y", "Y":
-			if m.suggestionMode && m.connected {
-				m.suggestionMode = false
-				m.showInput = true
-				m.input.Focus()
-				m.addMessage("Command approved and executing...", userMessage)
+	case "e":
+		if m.focusState == focusMessages && len(m.messages) > 0 && !m.waitingForReply {
+			return true, m.editSelectedMessage()
+		}
 
-				err := m.agentClient.SendCommandApproval(true)
-				if err != nil {
-					m.addMessage(fmt.Sprintf("Error sending approval: %v", err), errorMessage)
-				}
-			} else if m.retryMode && m.connected {
-				m.retryMode = false
-				m.showInput = true
-				m.input.Focus()
-				m.addMessage("Retrying with a different approach...", userMessage)
+	case "y", "Y":
+		if m.suggestionMode && m.connected {
+			m.suggestionMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.metrics.approved++
+			m.addMessage("Command approved and executing...", userMessage)
+
+			err := m.agentClient.SendCommandApproval(true)
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error sending approval: %v", err), errorMessage)
+			}
+			return true, nil
+		} else if m.retryMode && m.connected {
+			m.retryMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.addMessage("Retrying with a different approach...", userMessage)
+
+			err := m.agentClient.SendRetryResponse(true)
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error sending retry response: %v", err), errorMessage)
+			}
+			return true, nil
+		} else if m.toolCallMode {
+			m.toolCallMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.addMessage(fmt.Sprintf("Tool %q approved and executing...", m.currentTool.Name), userMessage)
+			m.respondToToolCall(m.currentTool, true)
+			return true, nil
+		}
 
-				err := m.agentClient.SendRetryResponse(true)
-				if err != nil {
-					m.addMessage(fmt.Sprintf("Error sending retry response: %v", err), errorMessage)
-				}
+	case "n", "N":
+		if m.suggestionMode && m.connected {
+			m.suggestionMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.metrics.rejected++
+			m.addMessage("Command rejected", userMessage)
+
+			err := m.agentClient.SendCommandApproval(false)
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error sending rejection: %v", err), errorMessage)
+			}
+			return true, nil
+		} else if m.retryMode && m.connected {
+			m.retryMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.addMessage("Retry cancelled", userMessage)
+
+			err := m.agentClient.SendRetryResponse(false)
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error sending retry response: %v", err), errorMessage)
 			}
+			return true, nil
+		} else if m.toolCallMode {
+			m.toolCallMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.addMessage(fmt.Sprintf("Tool %q rejected", m.currentTool.Name), userMessage)
+			m.respondToToolCall(m.currentTool, false)
+			return true, nil
+		}
 
-		case "n", "N":
-			if m.suggestionMode && m.connected {
-				m.suggestionMode = false
-				m.showInput = true
-				m.input.Focus()
-				m.addMessage("Command rejected", userMessage)
+	case "a", "A":
+		if m.toolCallMode {
+			m.toolCallMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.setToolPolicy(m.currentTool.Name, auth.PolicyAlways)
+			m.addMessage(fmt.Sprintf("Tool %q approved and will always be allowed", m.currentTool.Name), userMessage)
+			m.respondToToolCall(m.currentTool, true)
+			return true, nil
+		}
 
-				err := m.agentClient.SendCommandApproval(false)
-				if err != nil {
-					m.addMessage(fmt.Sprintf("Error sending rejection: %v", err), errorMessage)
-				}
-			} else if m.retryMode && m.connected {
-				m.retryMode = false
-				m.showInput = true
-				m.input.Focus()
-				m.addMessage("Retry cancelled", userMessage)
+	case "x", "X":
+		if m.toolCallMode {
+			m.toolCallMode = false
+			m.showInput = true
+			m.input.Focus()
+			m.setToolPolicy(m.currentTool.Name, auth.PolicyNever)
+			m.addMessage(fmt.Sprintf("Tool %q rejected and will never be allowed", m.currentTool.Name), userMessage)
+			m.respondToToolCall(m.currentTool, false)
+			return true, nil
+		}
 
-				err := m.agentClient.SendRetryResponse(false)
-				if err != nil {
-					m.addMessage(fmt.Sprintf("Error sending retry response: %v", err), errorMessage)
-				}
+	case "ctrl+e":
+		if m.suggestionMode {
+			edited, err := ExternalEditor(strings.Join(m.currentCommand, " "))
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error opening editor: %v", err), errorMessage)
+				return true, nil
 			}
-		default:
-			// Only update input for other keys (not enter/ctrl+enter)
-			if m.showInput && !m.suggestionMode && !m.retryMode {
-				m.input, cmd = m.input.Update(msg)
-				cmds = append(cmds, cmd)
+			m.currentCommand = strings.Fields(edited)
+			if m.connected {
+				if err := m.agentClient.SendCommandEdit(m.currentCommand); err != nil {
+					m.addMessage(fmt.Sprintf("Error sending edited command: %v", err), errorMessage)
+				}
 			}
+			m.addMessage(fmt.Sprintf("Command edited: %s", strings.Join(m.currentCommand, " ")), userMessage)
+			return true, nil
 		}
-	default:
-		// Update input for non-key messages
-		if m.showInput && !m.suggestionMode && !m.retryMode {
-			m.input, cmd = m.input.Update(msg)
-			cmds = append(cmds, cmd)
+		if m.showInput && !m.retryMode && !m.toolCallMode && !m.waitingForReply {
+			edited, err := ExternalEditor(m.input.Value())
+			if err != nil {
+				m.addMessage(fmt.Sprintf("Error opening editor: %v", err), errorMessage)
+				return true, nil
+			}
+			m.input.SetValue(strings.TrimRight(edited, "\n"))
 		}
+		return true, nil
 	}
 
-	// Always update viewport
-	m.viewport, cmd = m.viewport.Update(msg)
-	cmds = append(cmds, cmd)
-
-	m.updateViewportContent()
-
-	return m, tea.Batch(cmds...)
+	// Only update input for other keys (not enter/ctrl+enter)
+	if m.showInput && !m.suggestionMode && !m.retryMode && !m.toolCallMode {
+		m.input, cmd = m.input.Update(msg)
+	}
+	return true, cmd
 }
 
 // View renders the chat interface
@@ -358,6 +1068,10 @@ func (m ChatModel) View() string {
 		inputView = m.renderCommandSuggestion()
 	} else if m.retryMode {
 		inputView = m.renderRetryPrompt()
+	} else if m.toolCallMode {
+		inputView = m.renderToolCallSuggestion()
+	} else if m.focusState == focusMessages {
+		inputView = m.renderMessageNav()
 	} else if m.showInput {
 		inputView = m.renderInput()
 	}
@@ -365,31 +1079,119 @@ func (m ChatModel) View() string {
 	sections = append(sections, connectionStatus)
 	sections = append(sections, chatView)
 	sections = append(sections, inputView)
+	sections = append(sections, m.renderMetricsFooter())
 
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
+// metricsFooterStyle renders the session metrics line in a dim, compact
+// style so it reads as ambient status rather than part of the transcript.
+var metricsFooterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+
+// renderMetricsFooter summarizes the session's token usage, elapsed
+// time since the first prompt, and command/tool approval counts.
+func (m ChatModel) renderMetricsFooter() string {
+	elapsed := time.Duration(0)
+	if !m.metrics.startedAt.IsZero() {
+		elapsed = time.Since(m.metrics.startedAt).Round(time.Second)
+	}
+
+	line := fmt.Sprintf(
+		"tokens: %d sent / %d received • elapsed: %s • approved: %d • rejected: %d • retries: %d",
+		m.metrics.promptTokens, m.metrics.completionTokens, elapsed,
+		m.metrics.approved, m.metrics.rejected, m.metrics.retryRounds,
+	)
+	return metricsFooterStyle.Render(line)
+}
+
 // addMessage adds a message to the chat
 func (m *ChatModel) addMessage(content string, msgType messageType) {
 	m.messages = append(m.messages, message{
 		content: content,
 		msgType: msgType,
 	})
+	m.persistMessage(content, msgType)
 	m.updateViewportContent()
 }
 
+// persistMessage saves a message to the history store and, if attached, the
+// branching conversation tree - appending it as a child of the active
+// branch's tip and advancing currentNodeID to it.
+func (m *ChatModel) persistMessage(content string, msgType messageType) {
+	role, ok := historyRole(msgType)
+	if !ok {
+		return
+	}
+
+	if m.historyStore != nil && m.conversationID != "" {
+		m.historyStore.SaveMessage(m.conversationID, history.StoredMessage{
+			Role:    role,
+			Content: content,
+			Time:    time.Now(),
+		})
+	}
+
+	if m.convStore != nil && m.convTree != nil {
+		if node, err := m.convStore.Append(m.convTree.ID, m.currentNodeID, role, content); err == nil {
+			m.currentNodeID = node.ID
+		}
+	}
+}
+
+// historyRole maps a chat messageType to the role stored in history.
+func historyRole(msgType messageType) (string, bool) {
+	switch msgType {
+	case userMessage:
+		return "user", true
+	case agentMessage:
+		return "agent", true
+	case commandOutput:
+		return "command_output", true
+	case errorMessage:
+		return "error", true
+	case toolResult:
+		return "tool_result", true
+	}
+	return "", false
+}
+
 // addCommandSuggestion adds a command suggestion to the chat
-func (m *ChatModel) addCommandSuggestion(command []string, explanation string) {
+func (m *ChatModel) addCommandSuggestion(command []string, explanation string, id string) {
 	m.messages = append(m.messages, message{
 		content:     explanation,
 		msgType:     commandSuggestion,
 		command:     command,
 		explanation: explanation,
+		id:          id,
 	})
 	m.showInput = false
 	m.updateViewportContent()
 }
 
+// resolveCommandOutput links output to the most recent unresolved
+// commandSuggestion message - the one whose id matches, or (when id is
+// empty, e.g. an older server not sending one) simply the most recent
+// unresolved suggestion - so the result renders as part of that message
+// instead of an independent one. It reports whether a match was found.
+func (m *ChatModel) resolveCommandOutput(output, id string) bool {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := &m.messages[i]
+		if msg.msgType != commandSuggestion || msg.hasResult {
+			continue
+		}
+		if id != "" && msg.id != id {
+			continue
+		}
+		msg.hasResult = true
+		msg.resultOutput = output
+		msg.resultSuccess = true
+		m.invalidateMessageCache(i)
+		m.persistMessage(output, commandOutput)
+		return true
+	}
+	return false
+}
+
 // addRetryRequest adds a retry request to the chat
 func (m *ChatModel) addRetryRequest(content string, retryCount int) {
 	m.messages = append(m.messages, message{
@@ -401,30 +1203,162 @@ func (m *ChatModel) addRetryRequest(content string, retryCount int) {
 	m.updateViewportContent()
 }
 
-// updateViewportContent updates the viewport with current messages
+// addToolCallSuggestion adds a structured tool-call approval prompt to the chat
+func (m *ChatModel) addToolCallSuggestion(tool client.ToolCall) {
+	m.messages = append(m.messages, message{
+		content: tool.Name,
+		msgType: toolCallSuggestion,
+		tool:    tool,
+	})
+	m.showInput = false
+	m.updateViewportContent()
+}
+
+// respondToToolCall sends an approval/rejection for a tool call and
+// reports any send error back into the chat, mirroring how command
+// approvals surface connection errors to the user.
+func (m *ChatModel) respondToToolCall(tool client.ToolCall, approved bool) {
+	if approved {
+		m.metrics.approved++
+	} else {
+		m.metrics.rejected++
+	}
+	if !m.connected {
+		return
+	}
+	if err := m.agentClient.SendToolApproval(tool.ID, approved); err != nil {
+		m.addMessage(fmt.Sprintf("Error sending tool approval: %v", err), errorMessage)
+	}
+}
+
+// setToolPolicy remembers the user's always/never decision for a tool by
+// name and persists it alongside the API key file so future calls to the
+// same tool don't prompt again.
+func (m *ChatModel) setToolPolicy(name string, policy auth.ToolPolicy) {
+	if m.toolPolicies == nil {
+		m.toolPolicies = map[string]auth.ToolPolicy{}
+	}
+	m.toolPolicies[name] = policy
+	if err := auth.SaveToolPolicies(m.toolPolicies); err != nil {
+		m.addMessage(fmt.Sprintf("Error saving tool policy: %v", err), errorMessage)
+	}
+}
+
+// addToolResult adds a tool call's execution result to the chat
+func (m *ChatModel) addToolResult(output string, success bool) {
+	m.messages = append(m.messages, message{
+		content:     output,
+		msgType:     toolResult,
+		toolSuccess: success,
+	})
+	m.persistMessage(output, toolResult)
+	m.updateViewportContent()
+}
+
+// resolveToolResult links output/success to the toolCallSuggestion message
+// whose tool.ID matches toolCallID, the structured-tool counterpart to
+// resolveCommandOutput. It reports whether a match was found.
+func (m *ChatModel) resolveToolResult(toolCallID, output string, success bool) bool {
+	if toolCallID == "" {
+		return false
+	}
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := &m.messages[i]
+		if msg.msgType != toolCallSuggestion || msg.hasResult || msg.tool.ID != toolCallID {
+			continue
+		}
+		msg.hasResult = true
+		msg.resultOutput = output
+		msg.resultSuccess = success
+		m.invalidateMessageCache(i)
+		m.persistMessage(output, toolResult)
+		return true
+	}
+	return false
+}
+
+// selectedMessageStyle outlines the message selectedMessage points at
+// while focusState is focusMessages, the way a pager highlights the line
+// under its own cursor independent of the terminal's text selection.
+var selectedMessageStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderLeft(true).
+	BorderForeground(lipgloss.Color("#ffaa00")).
+	Padding(0, 1)
+
+// updateViewportContent updates the viewport with current messages,
+// recording each message's starting line in messageOffsets so moving
+// selectedMessage can scroll the viewport straight to it. Rendered
+// bodies are cached per message in messageCache, keyed by index, since
+// syntax-highlighting and wrapping command output is comparatively
+// expensive and most messages never change after they're added; the
+// message actively streaming in is always recomputed, and a resize or
+// invalidateMessageCache(i) call forces a specific entry to re-render.
 func (m *ChatModel) updateViewportContent() {
+	if len(m.messageCache) != len(m.messages) {
+		grown := make([]string, len(m.messages))
+		copy(grown, m.messageCache)
+		m.messageCache = grown
+	}
+
 	var content strings.Builder
+	m.messageOffsets = make([]int, len(m.messages))
+
+	for i, msg := range m.messages {
+		m.messageOffsets[i] = strings.Count(content.String(), "\n")
+		streamingLast := i == len(m.messages)-1 && m.waitingForReply && msg.msgType == agentMessage
+
+		body := m.messageCache[i]
+		if body == "" || streamingLast {
+			switch msg.msgType {
+			case userMessage:
+				body = m.formatUserMessage(msg.content)
+			case agentMessage:
+				body = m.formatAgentMessage(msg.content)
+			case commandSuggestion:
+				body = m.formatCommandSuggestion(msg)
+			case commandOutput:
+				body = m.formatCommandOutput(msg.content)
+			case errorMessage:
+				body = m.formatErrorMessage(msg.content)
+			case retryRequest:
+				body = m.formatRetryRequest(msg.content, msg.retryCount)
+			case toolCallSuggestion:
+				body = m.formatToolCallSuggestion(msg)
+			case toolResult:
+				body = m.formatToolResult(msg.content, msg.toolSuccess)
+			}
+			if !streamingLast {
+				m.messageCache[i] = body
+			}
+		}
+		if streamingLast {
+			body += m.cursor.View()
+		}
 
-	for _, msg := range m.messages {
-		switch msg.msgType {
-		case userMessage:
-			content.WriteString(m.formatUserMessage(msg.content))
-		case agentMessage:
-			content.WriteString(m.formatAgentMessage(msg.content))
-		case commandSuggestion:
-			content.WriteString(m.formatCommandSuggestion(msg.command, msg.explanation))
-		case commandOutput:
-			content.WriteString(m.formatCommandOutput(msg.content))
-		case errorMessage:
-			content.WriteString(m.formatErrorMessage(msg.content))
-		case retryRequest:
-			content.WriteString(m.formatRetryRequest(msg.content, msg.retryCount))
+		if m.focusState == focusMessages && i == m.selectedMessage {
+			body = selectedMessageStyle.Render(body)
 		}
+
+		content.WriteString(body)
 		content.WriteString("\n\n")
 	}
 
 	m.viewport.SetContent(content.String())
-	m.viewport.GotoBottom()
+	if m.focusState == focusMessages && len(m.messageOffsets) > 0 {
+		m.viewport.SetYOffset(m.messageOffsets[m.selectedMessage])
+	} else {
+		m.viewport.GotoBottom()
+	}
+}
+
+// invalidateMessageCache forces message i's cached rendering to be
+// recomputed the next time updateViewportContent runs, e.g. after
+// editSelectedMessage rewrites its content in place.
+func (m *ChatModel) invalidateMessageCache(i int) {
+	if i >= 0 && i < len(m.messageCache) {
+		m.messageCache[i] = ""
+	}
 }
 
 // Message formatting methods
@@ -439,29 +1373,90 @@ func (m *ChatModel) formatAgentMessage(content string) string {
 	style := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#0099ff")).
 		Bold(true)
-	return style.Render("Agent: ") + content
+	return style.Render("Agent: ") + m.renderMessage(content)
 }
 
-func (m *ChatModel) formatCommandSuggestion(command []string, explanation string) string {
+func (m *ChatModel) formatCommandSuggestion(msg message) string {
 	headerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#ffaa00")).
 		Bold(true)
-	commandStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffffff")).
-		Background(lipgloss.Color("#333333")).
-		Padding(0, 1)
 
 	header := headerStyle.Render("Command Suggestion:")
-	commandText := commandStyle.Render(strings.Join(command, " "))
+	commandText := m.wrapContent(m.renderShellCommand(msg.command))
+
+	if !msg.hasResult {
+		return fmt.Sprintf("%s\n%s\n%s", header, msg.explanation, commandText)
+	}
+
+	if !m.showToolResults {
+		return resultSummaryLine(msg.resultSuccess, strings.Join(msg.command, " "), msg.resultOutput)
+	}
 
-	return fmt.Sprintf("%s\n%s\n%s", header, explanation, commandText)
+	result := m.wrapContent(m.renderOutput(msg.resultOutput))
+	return fmt.Sprintf("%s\n%s\n%s\n%s", header, msg.explanation, commandText, result)
+}
+
+// resultSummaryLine renders the one-line collapsed form ctrl+t switches a
+// resolved command/tool result to: a success/failure icon, label, and the
+// output's line count, instead of the full output block.
+func resultSummaryLine(success bool, label, output string) string {
+	icon := "✔"
+	if !success {
+		icon = "✘"
+	}
+	lines := 0
+	if trimmed := strings.TrimRight(output, "\n"); strings.TrimSpace(trimmed) != "" {
+		lines = strings.Count(trimmed, "\n") + 1
+	}
+	return fmt.Sprintf("%s %s (%d lines)", icon, label, lines)
 }
 
 func (m *ChatModel) formatCommandOutput(content string) string {
 	style := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888888")).
 		Italic(true)
-	return style.Render("Output: ") + content
+	return style.Render("Output: ") + m.wrapContent(m.renderOutput(content))
+}
+
+// wrapContent word-wraps content to the viewport's current width unless
+// the user has toggled wrapping off (ctrl+w), for wide kubectl/terraform
+// output that reads better scrolled horizontally than broken mid-line.
+func (m *ChatModel) wrapContent(content string) string {
+	if !m.wrap {
+		return content
+	}
+	return WrapToWidth(content, m.viewport.Width-4)
+}
+
+// renderMessage syntax-highlights content via m.renderer, falling back to
+// content unchanged if no renderer was built (e.g. an invalid theme).
+func (m *ChatModel) renderMessage(content string) string {
+	if m.renderer == nil {
+		return content
+	}
+	return m.renderer.RenderMessage(content)
+}
+
+// renderShellCommand highlights command as a shell block, falling back
+// to the plain joined command if no renderer was built.
+func (m *ChatModel) renderShellCommand(command []string) string {
+	if m.renderer == nil {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ffffff")).
+			Background(lipgloss.Color("#333333")).
+			Padding(0, 1).
+			Render(strings.Join(command, " "))
+	}
+	return m.renderer.RenderShellCommand(command)
+}
+
+// renderOutput auto-detects JSON/YAML in content via m.renderer, falling
+// back to content unchanged if no renderer was built.
+func (m *ChatModel) renderOutput(content string) string {
+	if m.renderer == nil {
+		return content
+	}
+	return m.renderer.RenderOutput(content)
 }
 
 func (m *ChatModel) formatErrorMessage(content string) string {
@@ -480,6 +1475,70 @@ func (m *ChatModel) formatRetryRequest(content string, retryCount int) string {
 	return fmt.Sprintf("%s\n%s", header, content)
 }
 
+func (m *ChatModel) formatToolCallSuggestion(msg message) string {
+	tool := msg.tool
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#ffaa00")).
+		Bold(true)
+	badgeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#000000")).
+		Background(lipgloss.Color("#ffaa00")).
+		Padding(0, 1)
+	argsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#ffffff")).
+		Background(lipgloss.Color("#333333")).
+		Padding(0, 1)
+
+	header := headerStyle.Render("Tool Call: " + tool.Name)
+	badge := badgeStyle.Render(toolScopeLabel(tool.Scope))
+	args := argsStyle.Render(m.renderOutput(prettyToolArgs(tool.Args)))
+
+	if !msg.hasResult {
+		return fmt.Sprintf("%s %s\n%s", header, badge, args)
+	}
+
+	if !m.showToolResults {
+		return resultSummaryLine(msg.resultSuccess, tool.Name, msg.resultOutput)
+	}
+
+	result := m.wrapContent(m.renderOutput(msg.resultOutput))
+	return fmt.Sprintf("%s %s\n%s\n%s", header, badge, args, result)
+}
+
+func (m *ChatModel) formatToolResult(content string, success bool) string {
+	style := lipgloss.NewStyle().Italic(true)
+	label := "Tool Result: "
+	if success {
+		style = style.Foreground(lipgloss.Color("#888888"))
+	} else {
+		style = style.Foreground(lipgloss.Color("#ff0000"))
+		label = "Tool Error: "
+	}
+	return style.Render(label) + content
+}
+
+// toolScopeLabel returns a display label for a tool's requested scope,
+// defaulting to "unscoped" when the agent didn't set one.
+func toolScopeLabel(scope string) string {
+	if scope == "" {
+		return "unscoped"
+	}
+	return scope
+}
+
+// prettyToolArgs renders a tool call's JSON args indented for display,
+// falling back to the raw bytes if they don't parse.
+func prettyToolArgs(args json.RawMessage) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, args, "", "  "); err != nil {
+		return string(args)
+	}
+	return buf.String()
+}
+
 // renderInput renders the input area
 func (m *ChatModel) renderInput() string {
 	inputView := m.input.View()
@@ -488,11 +1547,20 @@ func (m *ChatModel) renderInput() string {
 	instructions := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888888")).
 		Italic(true).
-		Render("Press Enter to send • Ctrl+C to exit")
+		Render("Press Enter to send • Ctrl+C to exit • Tab to browse messages • Ctrl+W to toggle wrap • Ctrl+T to toggle results")
 
 	return lipgloss.JoinVertical(lipgloss.Left, inputView, instructions)
 }
 
+// renderMessageNav renders the hint bar shown while focusState is
+// focusMessages, in place of the textarea.
+func (m *ChatModel) renderMessageNav() string {
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#888888")).
+		Italic(true)
+	return style.Render("j/k to move • e to edit in $EDITOR • Tab to return to input")
+}
+
 // renderCommandSuggestion renders the command approval prompt
 func (m *ChatModel) renderCommandSuggestion() string {
 	style := lipgloss.NewStyle().
@@ -501,8 +1569,12 @@ func (m *ChatModel) renderCommandSuggestion() string {
 		Foreground(lipgloss.Color("#ffaa00"))
 
 	commandStr := strings.Join(m.currentCommand, " ")
-	prompt := fmt.Sprintf("Execute command: %s\n\n%s\n\nApprove? (y/n)",
-		commandStr, m.currentExplanation)
+	displayCommand := commandStr
+	if LooksLikeCommand(commandStr) {
+		displayCommand = HighlightCommand(commandStr)
+	}
+	prompt := fmt.Sprintf("Execute command: %s\n\n%s\n\nApprove? (y/n) • edit in $EDITOR (ctrl+e)",
+		displayCommand, m.currentExplanation)
 
 	return style.Render(prompt)
 }
@@ -519,3 +1591,16 @@ func (m *ChatModel) renderRetryPrompt() string {
 
 	return style.Render(prompt)
 }
+
+// renderToolCallSuggestion renders the tool-call approval prompt
+func (m *ChatModel) renderToolCallSuggestion() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(1).
+		Foreground(lipgloss.Color("#ffaa00"))
+
+	prompt := fmt.Sprintf("Run tool %q (%s)?\n\n%s\n\nApprove? (y/n) • always/never for this tool (a/x)",
+		m.currentTool.Name, toolScopeLabel(m.currentTool.Scope), prettyToolArgs(m.currentTool.Args))
+
+	return style.Render(prompt)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// commandBinaries names the runtime CLIs HighlightCommand recognizes as
+// the start of a shell command worth highlighting.
+var commandBinaries = map[string]bool{
+	"docker": true, "podman": true, "singularity": true,
+}
+
+// dockerVerbs names known container-runtime subcommands, styled
+// distinctly from plain arguments by HighlightCommand.
+var dockerVerbs = map[string]bool{
+	"ps": true, "logs": true, "images": true, "network": true,
+	"start": true, "stop": true, "restart": true, "inspect": true,
+	"connect": true, "ls": true, "pull": true, "run": true, "exec": true,
+	"build": true, "rm": true, "rmi": true,
+}
+
+var (
+	commandBinaryStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	commandVerbStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	commandFlagStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("105"))
+	commandArgStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+)
+
+// LooksLikeCommand reports whether line's first word is a runtime binary
+// HighlightCommand knows how to colorize, so callers can skip
+// highlighting plain chat text.
+func LooksLikeCommand(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && commandBinaries[fields[0]]
+}
+
+// HighlightCommand colorizes line as a shell command: the binary
+// ("docker", "podman"), subcommand verbs ("ps", "logs", "network"),
+// flags ("-a", "--format"), and everything else (container/image/network
+// identifiers) each get their own lipgloss style. It's a best-effort
+// tokenizer by whitespace, not a real shell parser - quoting and
+// escaping aren't honored - which is fine for previewing a suggested or
+// composed command, the only thing it's used for.
+func HighlightCommand(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return line
+	}
+
+	tokens := make([]string, len(fields))
+	for i, field := range fields {
+		switch {
+		case i == 0:
+			tokens[i] = commandBinaryStyle.Render(field)
+		case strings.HasPrefix(field, "-"):
+			tokens[i] = commandFlagStyle.Render(field)
+		case dockerVerbs[field]:
+			tokens[i] = commandVerbStyle.Render(field)
+		default:
+			tokens[i] = commandArgStyle.Render(field)
+		}
+	}
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,71 @@
+package ui
+
+import "strings"
+
+// CompletionProvider supplies the dynamic values tab-completion can offer
+// for a command being composed - container names, image refs, and
+// network names - without MultilineModel needing to know how they were
+// obtained (a live CommandService, a mock, or a test fixture).
+type CompletionProvider interface {
+	Containers() []string
+	Images() []string
+	Networks() []string
+}
+
+// commandVocabulary lists the static subcommands and flags tab-completion
+// offers regardless of CompletionProvider, keyed by the verb they follow
+// ("" means "no verb yet", i.e. completing the subcommand itself).
+var commandVocabulary = map[string][]string{
+	"":        {"ps", "logs", "images", "network", "start", "stop", "restart", "inspect", "ls", "pull", "run", "exec", "build", "rm", "rmi"},
+	"ps":      {"-a", "--all", "--format", "--filter", "-q"},
+	"logs":    {"-f", "--follow", "--tail", "--since", "--timestamps"},
+	"images":  {"-a", "--all", "--format", "--filter"},
+	"network": {"ls", "inspect", "create", "rm", "connect", "disconnect"},
+	"run":     {"-d", "--detach", "-it", "--rm", "--name", "-p", "--env"},
+	"exec":    {"-it", "-i", "-t"},
+}
+
+// lastVerb returns fields[1], the word after the binary (fields[0], e.g.
+// "docker"), whether or not it's a known subcommand, or "" if the command
+// doesn't have one yet - used to pick which vocabulary to complete from.
+func lastVerb(fields []string) string {
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// completeWord returns every candidate that starts with prefix, drawn
+// from the static vocabulary for line's current verb plus, when prefix
+// doesn't look like a flag, the dynamic values provider supplies.
+func completeWord(line string, provider CompletionProvider) []string {
+	fields := strings.Fields(line)
+	prefix := ""
+	if !strings.HasSuffix(line, " ") && len(fields) > 0 {
+		prefix = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var candidates []string
+	candidates = append(candidates, commandVocabulary[lastVerb(fields)]...)
+
+	if provider != nil && !strings.HasPrefix(prefix, "-") {
+		candidates = append(candidates, provider.Containers()...)
+		candidates = append(candidates, provider.Images()...)
+		candidates = append(candidates, provider.Networks()...)
+	}
+
+	var matches []string
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if prefix != "" && !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+		matches = append(matches, candidate)
+	}
+	return matches
+}
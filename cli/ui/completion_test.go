@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// stubCompletionProvider is the mock CompletionProvider SetCompletionProvider
+// exists to let tests inject, in place of a live CommandService.
+type stubCompletionProvider struct {
+	containers []string
+	images     []string
+	networks   []string
+}
+
+func (s stubCompletionProvider) Containers() []string { return s.containers }
+func (s stubCompletionProvider) Images() []string     { return s.images }
+func (s stubCompletionProvider) Networks() []string   { return s.networks }
+
+func TestCompleteWordStaticVocabulary(t *testing.T) {
+	got := completeWord("docker l", nil)
+	want := []string{"logs", "ls"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("completeWord(%q, nil) = %v, want %v", "docker l", got, want)
+	}
+}
+
+func TestCompleteWordFlagsDontPullDynamicValues(t *testing.T) {
+	provider := stubCompletionProvider{containers: []string{"web-server"}}
+	got := completeWord("docker logs --f", provider)
+	want := []string{"--follow"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("completeWord(%q, provider) = %v, want %v", "docker logs --f", got, want)
+	}
+}
+
+func TestCompleteWordUsesProviderForIdentifiers(t *testing.T) {
+	provider := stubCompletionProvider{
+		containers: []string{"web-server", "worker"},
+		images:     []string{"nginx:latest"},
+		networks:   []string{"bridge"},
+	}
+
+	got := completeWord("docker logs w", provider)
+	want := []string{"web-server", "worker"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("completeWord(%q, provider) = %v, want %v", "docker logs w", got, want)
+	}
+}
+
+func TestCompleteWordNoProviderSkipsDynamicValues(t *testing.T) {
+	got := completeWord("docker logs web", nil)
+	if len(got) != 0 {
+		t.Fatalf("completeWord with nil provider = %v, want no matches", got)
+	}
+}
+
+func TestCompleteWordDeduplicatesCandidates(t *testing.T) {
+	provider := stubCompletionProvider{containers: []string{"ps-runner", "ps"}}
+	got := completeWord("docker p", provider)
+	want := []string{"ps", "pull", "ps-runner"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("completeWord(%q, provider) = %v, want %v", "docker p", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -16,6 +18,8 @@ type ConfirmationModel struct {
 	selected   bool // true for yes, false for no
 	result     *bool
 	style      lipgloss.Style
+	detail     []string
+	theme      string
 }
 
 // NewConfirmation creates a new confirmation dialog component
@@ -44,6 +48,21 @@ func (m ConfirmationModel) WithNoText(text string) ConfirmationModel {
 	return m
 }
 
+// WithDetail attaches a shell command to render, syntax-highlighted and
+// flagged when it looks state-modifying, below the question.
+func (m ConfirmationModel) WithDetail(command []string) ConfirmationModel {
+	m.detail = command
+	return m
+}
+
+// WithTheme sets the glamour theme ("dracula", "github", ...) used to
+// syntax-highlight WithDetail's command. The default is Renderer's own
+// default theme.
+func (m ConfirmationModel) WithTheme(theme string) ConfirmationModel {
+	m.theme = theme
+	return m
+}
+
 // Init initializes the confirmation dialog component
 func (m ConfirmationModel) Init() tea.Cmd {
 	return nil
@@ -51,24 +70,41 @@ func (m ConfirmationModel) Init() tea.Cmd {
 
 // Update handles updates to the confirmation dialog component
 func (m ConfirmationModel) Update(msg tea.Msg) (ConfirmationModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "left", "h":
-			m.selected = true
-		case "right", "l":
-			m.selected = false
-		case "enter":
-			result := m.selected
-			m.result = &result
-			return m, nil
-		case "esc":
-			return m, tea.Quit
-		}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
 	}
 	return m, nil
 }
 
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed.
+func (m *ConfirmationModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "left", "h":
+		m.selected = true
+		return true, nil
+	case "right", "l":
+		m.selected = false
+		return true, nil
+	case "enter":
+		result := m.selected
+		m.result = &result
+		return true, nil
+	case "esc":
+		return true, tea.Quit
+	case "ctrl+e":
+		if len(m.detail) == 0 {
+			return false, nil
+		}
+		if edited, err := ExternalEditor(strings.Join(m.detail, " ")); err == nil {
+			m.detail = strings.Fields(edited)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 // View renders the confirmation dialog component
 func (m ConfirmationModel) View() string {
 	var yesButton, noButton string
@@ -81,11 +117,19 @@ func (m ConfirmationModel) View() string {
 		noButton = m.focusStyle.Render(m.noStyle.Render(" " + m.noText + " "))
 	}
 
-	return m.style.Render(
-		m.question + "\n\n" +
-			"  " + yesButton + "  " + noButton + "\n\n" +
-			"  ← / → to navigate • enter to select",
-	)
+	body := m.question
+	if len(m.detail) > 0 {
+		if renderer, err := NewRenderer(m.theme); err == nil {
+			body += "\n\n" + renderer.RenderShellCommand(m.detail)
+		}
+	}
+
+	hint := "  ← / → to navigate • enter to select"
+	if len(m.detail) > 0 {
+		hint += " • ctrl+e to edit"
+	}
+
+	return m.style.Render(body + "\n\n" + "  " + yesButton + "  " + noButton + "\n\n" + hint)
 }
 
 // Result returns the result of the confirmation dialog
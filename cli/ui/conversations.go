@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+
+	"cloud-assist/internal/history"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conversationItem adapts a history.Conversation to the list.Item interface.
+type conversationItem struct {
+	conv history.Conversation
+}
+
+func (i conversationItem) Title() string { return i.conv.Title }
+
+func (i conversationItem) Description() string {
+	return fmt.Sprintf("%d messages • updated %s", len(i.conv.Messages), i.conv.UpdatedAt.Format("2006-01-02 15:04"))
+}
+
+func (i conversationItem) FilterValue() string { return i.conv.Title }
+
+// ConversationsModel shows a browsable list of past chat sessions.
+type ConversationsModel struct {
+	list     list.Model
+	store    *history.Store
+	selected *history.Conversation
+	style    lipgloss.Style
+}
+
+// NewConversationsModel creates a new conversations list backed by store.
+func NewConversationsModel(store *history.Store, width, height int) ConversationsModel {
+	l := list.New(nil, list.NewDefaultDelegate(), width, height)
+	l.Title = "Conversations"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = lipgloss.NewStyle().MarginLeft(2).Bold(true)
+
+	return ConversationsModel{
+		list:  l,
+		store: store,
+		style: lipgloss.NewStyle().BorderForeground(lipgloss.Color("62")).BorderStyle(lipgloss.RoundedBorder()),
+	}
+}
+
+// Init initializes the conversations list.
+func (m ConversationsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Refresh reloads the conversation list from the store.
+func (m *ConversationsModel) Refresh() error {
+	convs, err := m.store.ListConversations()
+	if err != nil {
+		return err
+	}
+
+	items := make([]list.Item, len(convs))
+	for i, conv := range convs {
+		items[i] = conversationItem{conv: conv}
+	}
+	m.list.SetItems(items)
+	return nil
+}
+
+// Update handles updates to the conversations list.
+func (m ConversationsModel) Update(msg tea.Msg) (ConversationsModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed.
+func (m *ConversationsModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	// Let esc fall through to the parent's global "go back" binding, unless
+	// the list is using it to cancel an active filter.
+	if msg.String() == "esc" && m.list.FilterState() != list.Filtering {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		if item, ok := m.list.SelectedItem().(conversationItem); ok {
+			conv := item.conv
+			m.selected = &conv
+		}
+	}
+
+	m.list, cmd = m.list.Update(msg)
+	return true, cmd
+}
+
+// View renders the conversations list.
+func (m ConversationsModel) View() string {
+	return m.style.Render(m.list.View())
+}
+
+// Selected returns the conversation chosen with enter, if any.
+func (m ConversationsModel) Selected() *history.Conversation {
+	return m.selected
+}
+
+// ClearSelection resets the pending selection after it has been consumed.
+func (m *ConversationsModel) ClearSelection() {
+	m.selected = nil
+}
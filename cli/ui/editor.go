@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// program is the running Bubble Tea program, recorded by SetProgram once
+// cmd/main.go constructs it, so ExternalEditor can release and restore the
+// terminal around a blocking editor invocation without every model in the
+// call chain threading a *tea.Program through.
+var program *tea.Program
+
+// SetProgram records p as the program ExternalEditor suspends. Call it
+// once, right after tea.NewProgram, before p.Run().
+func SetProgram(p *tea.Program) {
+	program = p
+}
+
+// ExternalEditor drops the user into $EDITOR (falling back to vi, then
+// nano) to compose or revise initial, the way lmcli lets you write and
+// edit prompts from the comfort of your own editor. It writes initial to
+// a tempfile, releases the terminal for the duration the same way
+// tea.ExecProcess does internally, runs the editor against the tempfile,
+// and returns its contents once the editor exits.
+func ExternalEditor(initial string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "cloud-assist-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create tempfile: %w", err)
+	}
+	path := tmpFile.Name()
+	defer os.Remove(path)
+
+	if _, err := tmpFile.WriteString(initial); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write tempfile: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to close tempfile: %w", err)
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if program != nil {
+		if err := program.ReleaseTerminal(); err != nil {
+			return "", fmt.Errorf("failed to release terminal: %w", err)
+		}
+		defer program.RestoreTerminal()
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q exited with error: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited tempfile: %w", err)
+	}
+	return string(edited), nil
+}
+
+// editorCommand resolves $EDITOR, falling back to vi and then nano when
+// it's unset, the way most terminal tools pick an editor.
+func editorCommand() string {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if _, err := exec.LookPath("vi"); err == nil {
+		return "vi"
+	}
+	return "nano"
+}
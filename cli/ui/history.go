@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// History is a readline-style recall ring for submitted input lines, with
+// an optional on-disk backing file so recall survives across sessions.
+// It is shared by TextInputModel and MultilineModel via
+// NewTextInputWithHistory / NewMultilineWithHistory.
+type History struct {
+	path    string
+	entries []string
+	cap     int
+	cursor  int // index into entries while recalling; len(entries) means "not recalling"
+	pending string
+}
+
+// DefaultHistoryPath returns "<user config dir>/cloud-assist/history".
+func DefaultHistoryPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "cloud-assist", "history"), nil
+}
+
+// NewHistory creates a history ring backed by path, loading any entries
+// already recorded there. A path of "" keeps the ring in memory only.
+// capacity bounds how many entries are kept (and written back to disk).
+func NewHistory(path string, capacity int) (*History, error) {
+	h := &History{path: path, cap: capacity}
+
+	if path == "" {
+		h.cursor = 0
+		return h, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			h.cursor = 0
+			return h, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.entries = append(h.entries, parseHistoryLine(scanner.Text()))
+	}
+	h.truncate()
+	h.cursor = len(h.entries)
+	return h, nil
+}
+
+// parseHistoryLine extracts the entry text from an on-disk history line,
+// which is either "<unix-timestamp>\t<entry>" (the current format) or a
+// bare entry (files written before timestamps were added).
+func parseHistoryLine(line string) string {
+	prefix, rest, found := strings.Cut(line, "\t")
+	if !found {
+		return line
+	}
+	if _, err := strconv.ParseInt(prefix, 10, 64); err != nil {
+		return line
+	}
+	return rest
+}
+
+// Add appends entry to the history, removing any earlier occurrence of
+// the same text (shell HISTCONTROL=erasedups semantics, so repeating a
+// command moves it to the end instead of cluttering recall with
+// duplicates), and persists it to disk if a path was given.
+func (h *History) Add(entry string) {
+	if entry == "" {
+		return
+	}
+
+	deduped := h.entries[:0:0]
+	for _, existing := range h.entries {
+		if existing != entry {
+			deduped = append(deduped, existing)
+		}
+	}
+	h.entries = append(deduped, entry)
+	h.truncate()
+	h.cursor = len(h.entries)
+
+	if h.path == "" {
+		return
+	}
+	if err := h.save(); err != nil {
+		// Recall degrades to in-memory-only; not worth surfacing to the UI.
+		return
+	}
+}
+
+// save rewrites the entire on-disk history file from h.entries, each
+// entry prefixed with the time it was written.
+func (h *History) save() error {
+	f, err := os.OpenFile(h.path, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	now := time.Now().Unix()
+	for _, entry := range h.entries {
+		if _, err := fmt.Fprintf(f, "%d\t%s\n", now, entry); err != nil {
+			return fmt.Errorf("failed to write history file: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *History) truncate() {
+	if h.cap <= 0 || len(h.entries) <= h.cap {
+		return
+	}
+	h.entries = h.entries[len(h.entries)-h.cap:]
+}
+
+// Prev moves the recall cursor back one entry (towards older input) and
+// returns it. current is the text in the field before the first Prev call
+// in a recall sequence, so Next can restore it once the user recalls back
+// to the present.
+func (h *History) Prev(current string) (string, bool) {
+	if h.cursor == len(h.entries) {
+		h.pending = current
+	}
+	if h.cursor == 0 {
+		return "", false
+	}
+	h.cursor--
+	return h.entries[h.cursor], true
+}
+
+// Next moves the recall cursor forward one entry (towards newer input),
+// returning the pending (pre-recall) text once the cursor reaches present.
+func (h *History) Next() (string, bool) {
+	if h.cursor >= len(h.entries) {
+		return "", false
+	}
+	h.cursor++
+	if h.cursor == len(h.entries) {
+		return h.pending, true
+	}
+	return h.entries[h.cursor], true
+}
+
+// Reset returns the recall cursor to the present, as if no recall had
+// happened. Called whenever the field's content changes by any means
+// other than Prev/Next.
+func (h *History) Reset() {
+	h.cursor = len(h.entries)
+}
+
+// SearchBack performs one step of an incremental reverse search (ctrl+r):
+// starting just before from, it returns the index and text of the nearest
+// earlier entry fuzzy-matching query (a case-insensitive subsequence
+// match, e.g. "dlsa" matches "docker ls -a"), or ok=false if none matches.
+func (h *History) SearchBack(query string, from int) (index int, value string, ok bool) {
+	if query == "" {
+		return 0, "", false
+	}
+	for i := from - 1; i >= 0; i-- {
+		if fuzzyContains(h.entries[i], query) {
+			return i, h.entries[i], true
+		}
+	}
+	return 0, "", false
+}
+
+// fuzzyContains reports whether query's characters all appear in text, in
+// order, case-insensitively, with any other characters interspersed.
+func fuzzyContains(text, query string) bool {
+	text, query = strings.ToLower(text), strings.ToLower(query)
+	i := 0
+	for _, r := range text {
+		if i == len(query) {
+			break
+		}
+		if r == rune(query[i]) {
+			i++
+		}
+	}
+	return i == len(query)
+}
+
+// Len returns the number of recorded entries.
+func (h *History) Len() int {
+	return len(h.entries)
+}
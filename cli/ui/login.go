@@ -30,27 +30,37 @@ func (m LoginModel) Init() tea.Cmd {
 }
 
 func (m LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
+	}
+
 	var cmd tea.Cmd
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC: // Handle Ctrl+C
-			return m, tea.Quit
-		case tea.KeyEnter:
-			// For demo purpose, accept any non-empty API key
-			if m.input.Value() != "" {
-				m.authenticated = true
-				m.apiKey = m.input.Value()
-				return m, tea.Quit
-			} else {
-				m.err = "API key cannot be empty"
-				m.input.SetValue("")
-			}
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed. The caller only falls through to
+// global key bindings when handled is false.
+func (m *LoginModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC: // Handle Ctrl+C
+		return true, tea.Quit
+	case tea.KeyEnter:
+		// For demo purpose, accept any non-empty API key
+		if m.input.Value() != "" {
+			m.authenticated = true
+			m.apiKey = m.input.Value()
+			return true, tea.Quit
 		}
+		m.err = "API key cannot be empty"
+		m.input.SetValue("")
+		return true, nil
 	}
 
 	m.input, cmd = m.input.Update(msg)
-	return m, cmd
+	return true, cmd
 }
 
 func (m LoginModel) View() string {
@@ -10,15 +10,34 @@ import (
 
 // MultilineModel represents a multiline text editor component
 type MultilineModel struct {
-	textarea textarea.Model
-	label    string
-	width    int
-	height   int
-	style    lipgloss.Style
+	textarea    textarea.Model
+	label       string
+	width       int
+	height      int
+	style       lipgloss.Style
+	history     *History
+	recalling   bool
+	searching   bool
+	searchQuery string
+	searchIndex int
+
+	completionProvider CompletionProvider
+	completing         bool
+	completions        []string
+	completionIndex    int
+	completionBase     string
 }
 
 // NewMultiline creates a new multiline text editor component
 func NewMultiline(label string, placeholder string, width, height int) MultilineModel {
+	return NewMultilineWithHistory(nil, label, placeholder, width, height)
+}
+
+// NewMultilineWithHistory creates a new multiline text editor backed by h,
+// so Up/Down recall previous submissions (while the editor is empty or
+// already showing a recalled entry) and ctrl+r does an incremental reverse
+// search over them. h may be nil to disable recall.
+func NewMultilineWithHistory(h *History, label string, placeholder string, width, height int) MultilineModel {
 	ta := textarea.New()
 	ta.Placeholder = placeholder
 	ta.Focus()
@@ -33,9 +52,18 @@ func NewMultiline(label string, placeholder string, width, height int) Multiline
 		width:    width,
 		height:   height,
 		style:    lipgloss.NewStyle().BorderForeground(lipgloss.Color("62")).BorderStyle(lipgloss.RoundedBorder()),
+		history:  h,
 	}
 }
 
+// SetCompletionProvider attaches p as the source of dynamic tab-completion
+// values (container names, image refs, network names). Passing nil
+// disables tab-completion, leaving tab to fall through to the textarea's
+// default handling.
+func (m *MultilineModel) SetCompletionProvider(p CompletionProvider) {
+	m.completionProvider = p
+}
+
 // Init initializes the multiline text editor component
 func (m MultilineModel) Init() tea.Cmd {
 	return textarea.Blink
@@ -43,33 +71,198 @@ func (m MultilineModel) Init() tea.Cmd {
 
 // Update handles updates to the multiline text editor component
 func (m MultilineModel) Update(msg tea.Msg) (MultilineModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
+	}
+
 	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
 
-	// Check for special keys before passing to textarea
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		switch keyMsg.String() {
-		case "shift+enter":
-			// Allow Shift+Enter to add new lines - convert to regular enter for textarea
-			enterMsg := tea.KeyMsg{
-				Type:  tea.KeyEnter,
-				Runes: []rune{'\n'},
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed. Enter and Ctrl+Enter are left
+// unhandled here (handled=false) so the parent chat model can treat them as
+// "send" without the keystroke also landing in the textarea.
+func (m *MultilineModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+
+	if m.completing {
+		switch msg.String() {
+		case "tab":
+			m.cycleCompletion(1)
+			return true, nil
+		case "shift+tab":
+			m.cycleCompletion(-1)
+			return true, nil
+		case "enter":
+			m.completing = false
+			return true, nil
+		case "esc":
+			m.completing = false
+			return true, nil
+		}
+		m.completing = false
+	}
+
+	switch msg.String() {
+	case "esc":
+		// Leave esc for the parent's global "go back" binding.
+		return false, nil
+	case "tab":
+		if m.completionProvider != nil {
+			m.startCompletion()
+			return true, nil
+		}
+	case "ctrl+r":
+		if m.history != nil {
+			m.searching = true
+			m.searchQuery = ""
+			m.searchIndex = m.history.Len()
+		}
+		return true, nil
+	case "up":
+		if m.history != nil && (m.recalling || m.textarea.Value() == "") {
+			if value, ok := m.history.Prev(m.textarea.Value()); ok {
+				m.textarea.SetValue(value)
+				m.recalling = true
+			}
+			return true, nil
+		}
+	case "down":
+		if m.history != nil && m.recalling {
+			if value, ok := m.history.Next(); ok {
+				m.textarea.SetValue(value)
 			}
-			m.textarea, cmd = m.textarea.Update(enterMsg)
-			return m, cmd
-		case "enter", "ctrl+enter":
-			// Don't pass Enter or Ctrl+Enter to textarea, let parent handle them
-			// But still update textarea for other keys
-			return m, nil
+			return true, nil
 		}
+	case "alt+enter":
+		// shift+enter isn't usable here: standard terminal reporting
+		// (what bubbletea v1 parses) collapses shift+enter to the same
+		// \r as plain enter, so the two are indistinguishable without
+		// the Kitty keyboard protocol v2 brings. alt+enter IS reported
+		// distinctly, so that's the newline binding - convert to a
+		// regular enter keystroke for the textarea to insert.
+		enterMsg := tea.KeyMsg{
+			Type:  tea.KeyEnter,
+			Runes: []rune{'\n'},
+		}
+		m.textarea, cmd = m.textarea.Update(enterMsg)
+		m.recalling = false
+		return true, cmd
+	case "enter", "ctrl+enter":
+		// Don't pass Enter or Ctrl+Enter to textarea, let parent handle them
+		return false, nil
 	}
 
 	m.textarea, cmd = m.textarea.Update(msg)
-	return m, cmd
+	m.recalling = false
+	if m.history != nil {
+		m.history.Reset()
+	}
+	return true, cmd
 }
 
+// handleSearchInput processes keys while an incremental reverse search
+// (ctrl+r) is active, updating the textarea's value to the nearest match.
+func (m *MultilineModel) handleSearchInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.searching = false
+		return true, nil
+	case "ctrl+r":
+		if idx, value, ok := m.history.SearchBack(m.searchQuery, m.searchIndex); ok {
+			m.searchIndex = idx
+			m.textarea.SetValue(value)
+		}
+		return true, nil
+	case "backspace":
+		if len(m.searchQuery) > 0 {
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+		}
+		return true, nil
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.searchQuery += string(msg.Runes)
+			m.searchIndex = m.history.Len()
+			if idx, value, ok := m.history.SearchBack(m.searchQuery, m.searchIndex); ok {
+				m.searchIndex = idx
+				m.textarea.SetValue(value)
+			}
+		}
+		return true, nil
+	}
+}
+
+// startCompletion computes the candidates for the word under the cursor
+// (assumed to be at the end of the text - the textarea library exposes
+// no cursor-column accessor, only the current row via Line(), so this is
+// a deliberate simplification covering the common "actively typing"
+// case) and, if there are any, shows the first one.
+func (m *MultilineModel) startCompletion() {
+	value := m.textarea.Value()
+	fields := strings.Fields(value)
+	m.completionBase = value
+	if !strings.HasSuffix(value, " ") && len(fields) > 0 {
+		m.completionBase = value[:len(value)-len(fields[len(fields)-1])]
+	}
+
+	m.completions = completeWord(value, m.completionProvider)
+	if len(m.completions) == 0 {
+		return
+	}
+	m.completionIndex = 0
+	m.completing = true
+	m.textarea.SetValue(m.completionBase + m.completions[0])
+}
+
+// cycleCompletion advances the shown candidate by delta (wrapping),
+// replacing the completed word with the newly selected candidate.
+func (m *MultilineModel) cycleCompletion(delta int) {
+	if len(m.completions) == 0 {
+		return
+	}
+	m.completionIndex = (m.completionIndex + delta + len(m.completions)) % len(m.completions)
+	m.textarea.SetValue(m.completionBase + m.completions[m.completionIndex])
+}
+
+// Submit records value (the text the caller is about to send) in history,
+// if one is attached. Callers that intercept Enter/Ctrl+Enter themselves
+// (e.g. ChatModel) should call this before clearing the editor.
+func (m *MultilineModel) Submit(value string) {
+	if m.history != nil {
+		m.history.Add(value)
+	}
+	m.recalling = false
+}
+
+// completionOverlayStyle renders the candidate list shown below the
+// editor while tab-completion is active.
+var completionOverlayStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Italic(true)
+
 // View renders the multiline text editor component
 func (m MultilineModel) View() string {
-	return m.style.Render(m.label + "\n" + m.textarea.View())
+	label := m.label
+	if m.searching {
+		label = "(reverse-i-search)`" + m.searchQuery + "'"
+	}
+
+	body := label + "\n" + m.textarea.View()
+
+	switch {
+	case m.completing:
+		body += "\n" + completionOverlayStyle.Render(strings.Join(m.completions, "  "))
+	case LooksLikeCommand(m.textarea.Value()):
+		// textarea has no per-token styling hook, so the colorized
+		// form is shown as a preview line underneath rather than
+		// in place.
+		body += "\n" + HighlightCommand(m.textarea.Value())
+	}
+
+	return m.style.Render(body)
 }
 
 // Value returns the current value of the multiline text editor
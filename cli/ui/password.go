@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PasswordModel prompts for a passphrase with the input masked, the way
+// LoginModel prompts for an API key in the clear. Used by the encrypted
+// credential fallback to ask for the passphrase that derives its file
+// encryption key.
+type PasswordModel struct {
+	input     textinput.Model
+	prompt    string
+	err       string
+	submitted bool
+	value     string
+}
+
+// NewPasswordModel creates a password prompt with the given label.
+func NewPasswordModel(prompt string) PasswordModel {
+	ti := textinput.New()
+	ti.Placeholder = "passphrase"
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '*'
+	ti.Focus()
+	ti.CharLimit = 128
+	ti.Width = 30
+	return PasswordModel{input: ti, prompt: prompt}
+}
+
+func (m PasswordModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m PasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed.
+func (m *PasswordModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return true, tea.Quit
+	case tea.KeyEnter:
+		if m.input.Value() == "" {
+			m.err = "passphrase cannot be empty"
+			return true, nil
+		}
+		m.value = m.input.Value()
+		m.submitted = true
+		return true, tea.Quit
+	}
+
+	m.input, cmd = m.input.Update(msg)
+	return true, cmd
+}
+
+func (m PasswordModel) View() string {
+	view := fmt.Sprintf("🔑 %s\n\n%s\n\nPress Enter to submit", m.prompt, m.input.View())
+	if m.err != "" {
+		view += fmt.Sprintf("\n\n❌ %s", m.err)
+	}
+	return view
+}
+
+// Submitted reports whether the passphrase was entered and confirmed.
+func (m PasswordModel) Submitted() bool {
+	return m.submitted
+}
+
+// Value returns the entered passphrase.
+func (m PasswordModel) Value() string {
+	return m.value
+}
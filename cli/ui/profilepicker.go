@@ -0,0 +1,21 @@
+package ui
+
+import "cloud-assist/internal/auth"
+
+// NewProfilePicker builds a SelectModel listing every credential profile
+// saved via auth.SaveAPIKey, so the user can switch between e.g. staging
+// and prod backends instead of the app always authenticating as
+// auth.DefaultProfile.
+func NewProfilePicker(width, height int) (SelectModel, error) {
+	profiles, err := auth.ListProfiles()
+	if err != nil {
+		return SelectModel{}, err
+	}
+
+	items := make([]SelectItem, len(profiles))
+	for i, name := range profiles {
+		items[i] = SelectItem{Title: name, Value: name}
+	}
+
+	return NewSelect("Profiles", items, width, height), nil
+}
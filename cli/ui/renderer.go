@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// defaultTheme is the glamour style applied when a Renderer isn't given
+// one explicitly.
+const defaultTheme = "dracula"
+
+// Renderer syntax-highlights fenced code blocks, shell commands, and
+// JSON/YAML output via glamour (which uses alecthomas/chroma internally
+// for the code spans), the way lmcli renders its TUI instead of emitting
+// raw strings through lipgloss styles alone.
+type Renderer struct {
+	theme string
+	term  *glamour.TermRenderer
+}
+
+// NewRenderer creates a Renderer using theme ("dracula", "github", ...),
+// the glamour style name applied to Markdown and fenced-code rendering.
+func NewRenderer(theme string) (*Renderer, error) {
+	if theme == "" {
+		theme = defaultTheme
+	}
+
+	term, err := glamour.NewTermRenderer(
+		glamour.WithStylePath(theme),
+		glamour.WithWordWrap(0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build renderer for theme %q: %w", theme, err)
+	}
+
+	return &Renderer{theme: theme, term: term}, nil
+}
+
+// RenderMessage highlights any fenced code blocks in content (agent
+// replies, explanations) via glamour's Markdown rendering. Content with
+// no fenced code is returned unchanged rather than paying for a render
+// that wouldn't change anything.
+func (r *Renderer) RenderMessage(content string) string {
+	if !strings.Contains(content, "```") {
+		return content
+	}
+
+	out, err := r.term.Render(content)
+	if err != nil {
+		return content
+	}
+	return strings.TrimRight(out, "\n")
+}
+
+// WrapToWidth word-wraps content to width using muesli/reflow, the same
+// wrapper glamour uses internally - applied here as a separate pass so
+// ChatModel can wrap already-rendered (ANSI-styled) command/output blocks
+// to the viewport's current width instead of glamour's own fixed wrap
+// baked in at render time. width <= 0 returns content unchanged.
+func WrapToWidth(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+	return wordwrap.String(content, width)
+}
+
+// mutatingCommandStyle marks a command RenderShellCommand judges likely
+// to modify files or remote state, so the approval prompt doesn't read
+// identically to a harmless read-only command.
+var mutatingCommandStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff6600")).Bold(true)
+
+// RenderShellCommand highlights command as a shell code block, prefixed
+// with a warning marker when it looks like it would modify files or
+// cluster state (kubectl apply, terraform apply, rm, aws s3 cp, ...).
+func (r *Renderer) RenderShellCommand(command []string) string {
+	block := r.RenderMessage("```bash\n" + strings.Join(command, " ") + "\n```")
+	if isMutatingCommand(command) {
+		return mutatingCommandStyle.Render("⚠ modifies files or remote state") + "\n" + block
+	}
+	return block
+}
+
+// isMutatingCommand reports whether command is one of the commands the
+// command-approval flow treats as modifying, rather than merely reading,
+// files or remote state.
+func isMutatingCommand(command []string) bool {
+	if len(command) == 0 {
+		return false
+	}
+
+	switch command[0] {
+	case "rm":
+		return true
+	case "kubectl":
+		return len(command) > 1 && (command[1] == "apply" || command[1] == "delete")
+	case "terraform":
+		return len(command) > 1 && (command[1] == "apply" || command[1] == "destroy")
+	case "aws":
+		return len(command) > 2 && command[1] == "s3" && (command[2] == "cp" || command[2] == "rm" || command[2] == "sync")
+	}
+	return false
+}
+
+// yamlKeyPattern matches a line beginning "key:", the cheap heuristic
+// RenderOutput uses to tell YAML from plain text.
+var yamlKeyPattern = regexp.MustCompile(`(?m)^[A-Za-z0-9_.-]+:\s`)
+
+// RenderOutput auto-detects JSON or YAML in content and highlights it
+// accordingly, falling back to content unchanged for plain text.
+func (r *Renderer) RenderOutput(content string) string {
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return r.RenderMessage("```json\n" + content + "\n```")
+	case yamlKeyPattern.MatchString(content):
+		return r.RenderMessage("```yaml\n" + content + "\n```")
+	default:
+		return content
+	}
+}
@@ -51,14 +51,9 @@ func (m SelectModel) Init() tea.Cmd {
 
 // Update handles updates to the select input component
 func (m SelectModel) Update(msg tea.Msg) (SelectModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			if i, ok := m.list.SelectedItem().(SelectItem); ok {
-				m.selected = &i
-			}
-		}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
 	}
 
 	var cmd tea.Cmd
@@ -66,6 +61,26 @@ func (m SelectModel) Update(msg tea.Msg) (SelectModel, tea.Cmd) {
 	return m, cmd
 }
 
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed.
+func (m *SelectModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	// Let esc fall through to the parent's global "go back" binding, unless
+	// the list is using it to cancel an active filter.
+	if msg.String() == "esc" && m.list.FilterState() != list.Filtering {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		if i, ok := m.list.SelectedItem().(SelectItem); ok {
+			m.selected = &i
+		}
+	}
+
+	m.list, cmd = m.list.Update(msg)
+	return true, cmd
+}
+
 // View renders the select input component
 func (m SelectModel) View() string {
 	return m.style.Render(m.list.View())
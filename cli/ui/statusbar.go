@@ -15,6 +15,7 @@ type StatusBarModel struct {
 	mode        string
 	contextSize int
 	status      string
+	branch      string
 	keyBindings []KeyBinding
 	clock       bool
 	width       int
@@ -63,6 +64,13 @@ func (m *StatusBarModel) SetStatus(status string) {
 	m.status = status
 }
 
+// SetBranch sets the current conversation branch label, e.g. a short node
+// ID, so the status bar can show which branch of a forked conversation is
+// active the same way it shows context size.
+func (m *StatusBarModel) SetBranch(branch string) {
+	m.branch = branch
+}
+
 // SetKeyBindings sets the displayed keyboard shortcuts
 func (m *StatusBarModel) SetKeyBindings(keyBindings []KeyBinding) {
 	m.keyBindings = keyBindings
@@ -114,6 +122,11 @@ func (m StatusBarModel) View() string {
 		leftSections = append(leftSections, contextSection)
 	}
 
+	if m.branch != "" {
+		branchSection := fmt.Sprintf("branch:%s", m.branch)
+		leftSections = append(leftSections, branchSection)
+	}
+
 	// Right sections: key bindings and clock
 	for _, kb := range m.keyBindings {
 		keySection := fmt.Sprintf("%s:%s", kb.Key, kb.Description)
@@ -8,14 +8,25 @@ import (
 
 // TextInputModel represents a text input component
 type TextInputModel struct {
-	textInput textinput.Model
-	label     string
-	width     int
-	style     lipgloss.Style
+	textInput   textinput.Model
+	label       string
+	width       int
+	style       lipgloss.Style
+	history     *History
+	searching   bool
+	searchQuery string
+	searchIndex int
 }
 
 // NewTextInput creates a new text input component
 func NewTextInput(label string, placeholder string, width int) TextInputModel {
+	return NewTextInputWithHistory(nil, label, placeholder, width)
+}
+
+// NewTextInputWithHistory creates a new text input component backed by h,
+// so Up/Down recall previous submissions and ctrl+r does an incremental
+// reverse search over them. h may be nil to disable recall.
+func NewTextInputWithHistory(h *History, label string, placeholder string, width int) TextInputModel {
 	ti := textinput.New()
 	ti.Placeholder = placeholder
 	ti.Focus()
@@ -27,6 +38,7 @@ func NewTextInput(label string, placeholder string, width int) TextInputModel {
 		label:     label,
 		width:     width,
 		style:     lipgloss.NewStyle().BorderForeground(lipgloss.Color("62")).BorderStyle(lipgloss.RoundedBorder()),
+		history:   h,
 	}
 }
 
@@ -37,14 +49,105 @@ func (m TextInputModel) Init() tea.Cmd {
 
 // Update handles updates to the text input component
 func (m TextInputModel) Update(msg tea.Msg) (TextInputModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		_, cmd := m.HandleInput(keyMsg)
+		return m, cmd
+	}
+
 	var cmd tea.Cmd
 	m.textInput, cmd = m.textInput.Update(msg)
 	return m, cmd
 }
 
+// HandleInput processes a key message, mutating the model in place and
+// reporting whether the key was consumed. TextInputModel has no special
+// keys of its own beyond history recall, so every other key is forwarded
+// to the embedded component except esc, which is left for the parent's
+// global "go back" binding.
+func (m *TextInputModel) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		return false, nil
+	case "ctrl+r":
+		if m.history != nil {
+			m.searching = true
+			m.searchQuery = ""
+			m.searchIndex = m.history.Len()
+		}
+		return true, nil
+	case "up":
+		if m.history != nil {
+			if value, ok := m.history.Prev(m.textInput.Value()); ok {
+				m.textInput.SetValue(value)
+				m.textInput.CursorEnd()
+			}
+			return true, nil
+		}
+	case "down":
+		if m.history != nil {
+			if value, ok := m.history.Next(); ok {
+				m.textInput.SetValue(value)
+				m.textInput.CursorEnd()
+			}
+			return true, nil
+		}
+	case "enter":
+		if m.history != nil {
+			m.history.Add(m.textInput.Value())
+		}
+	}
+
+	m.textInput, cmd = m.textInput.Update(msg)
+	if m.history != nil {
+		m.history.Reset()
+	}
+	return true, cmd
+}
+
+// handleSearchInput processes keys while an incremental reverse search
+// (ctrl+r) is active, updating m.textInput's value to the nearest match.
+func (m *TextInputModel) handleSearchInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	switch msg.String() {
+	case "esc", "enter":
+		m.searching = false
+		return true, nil
+	case "ctrl+r":
+		if idx, value, ok := m.history.SearchBack(m.searchQuery, m.searchIndex); ok {
+			m.searchIndex = idx
+			m.textInput.SetValue(value)
+			m.textInput.CursorEnd()
+		}
+		return true, nil
+	case "backspace":
+		if len(m.searchQuery) > 0 {
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+		}
+		return true, nil
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.searchQuery += string(msg.Runes)
+			m.searchIndex = m.history.Len()
+			if idx, value, ok := m.history.SearchBack(m.searchQuery, m.searchIndex); ok {
+				m.searchIndex = idx
+				m.textInput.SetValue(value)
+				m.textInput.CursorEnd()
+			}
+		}
+		return true, nil
+	}
+}
+
 // View renders the text input component
 func (m TextInputModel) View() string {
-	return m.style.Render(m.label + "\n" + m.textInput.View())
+	label := m.label
+	if m.searching {
+		label = "(reverse-i-search)`" + m.searchQuery + "'"
+	}
+	return m.style.Render(label + "\n" + m.textInput.View())
 }
 
 // Value returns the current value of the text input